@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// quoteIdent quotes an identifier with backticks, escaping any backticks within it
+func quoteIdent(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// quoteString quotes a value as a single-quoted SQL string literal
+func quoteString(v string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", "''").Replace(v) + "'"
+}
+
+// canonicalColumn renders a column definition in the form used by SHOW CREATE TABLE.  Generated column expressions are
+// not part of the summary, and are rendered as a placeholder.
+func canonicalColumn(cs columnSummary) string {
+	var b strings.Builder
+
+	extra := strings.ToUpper(cs.Extra)
+
+	fmt.Fprintf(&b, "%s %s", quoteIdent(cs.Name), cs.Type)
+
+	switch {
+	case strings.Contains(extra, "VIRTUAL GENERATED"):
+		b.WriteString(" GENERATED ALWAYS AS (...) VIRTUAL")
+	case strings.Contains(extra, "STORED GENERATED"):
+		b.WriteString(" GENERATED ALWAYS AS (...) STORED")
+	}
+
+	if cs.Nullable == "NO" {
+		b.WriteString(" NOT NULL")
+	}
+
+	switch {
+	case cs.Default != nil && strings.Contains(extra, "DEFAULT_GENERATED"):
+		fmt.Fprintf(&b, " DEFAULT %s", *cs.Default)
+	case cs.Default != nil:
+		fmt.Fprintf(&b, " DEFAULT %s", quoteString(*cs.Default))
+	case cs.Nullable != "NO" && !strings.Contains(extra, "GENERATED"):
+		b.WriteString(" DEFAULT NULL")
+	}
+
+	if strings.Contains(extra, "AUTO_INCREMENT") {
+		b.WriteString(" AUTO_INCREMENT")
+	}
+
+	if i := strings.Index(extra, "ON UPDATE "); i != -1 {
+		if f := strings.Fields(cs.Extra[i+len("ON UPDATE "):]); len(f) > 0 {
+			fmt.Fprintf(&b, " ON UPDATE %s", f[0])
+		}
+	}
+
+	if strings.Contains(extra, "INVISIBLE") {
+		b.WriteString(" INVISIBLE")
+	}
+
+	return b.String()
+}
+
+// canonicalIndex renders an index definition in the form used by SHOW CREATE TABLE
+func canonicalIndex(is indexSummary) string {
+	cols := make([]string, len(is.Columns))
+	for i, c := range is.Columns {
+		if strings.HasPrefix(c, "(") {
+			cols[i] = c
+		} else {
+			cols[i] = quoteIdent(c)
+		}
+	}
+
+	switch {
+	case is.Name == "PRIMARY":
+		return fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(cols, ","))
+	case is.Unique:
+		return fmt.Sprintf("UNIQUE KEY %s (%s)", quoteIdent(is.Name), strings.Join(cols, ","))
+	default:
+		return fmt.Sprintf("KEY %s (%s)", quoteIdent(is.Name), strings.Join(cols, ","))
+	}
+}
+
+// canonicalForeignKey renders a foreign key constraint in the form used by SHOW CREATE TABLE
+func canonicalForeignKey(fk foreignKeySummary) string {
+	cols := make([]string, len(fk.Columns))
+	for i, c := range fk.Columns {
+		cols[i] = quoteIdent(c)
+	}
+	refs := make([]string, len(fk.RefColumns))
+	for i, c := range fk.RefColumns {
+		refs[i] = quoteIdent(c)
+	}
+	return fmt.Sprintf(
+		"CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)",
+		quoteIdent(fk.Name),
+		strings.Join(cols, ", "),
+		quoteIdent(fk.RefTable),
+		strings.Join(refs, ", "),
+	)
+}
+
+// canonicalTable renders a CREATE TABLE or CREATE VIEW statement from a table summary.  Columns are listed in their
+// defined order, followed by the primary key, the remaining indexes and then the foreign keys ordered by name, so that
+// the same table renders identically regardless of the source it was read from.
+func canonicalTable(ts tableSummary, db string) string {
+	if ts.Type == tableTypeView {
+		return fmt.Sprintf("CREATE VIEW %s AS %s;", quoteIdent(ts.Name), normalizeViewDefinition(ts.Definition, db))
+	}
+
+	lines := make([]string, 0, len(ts.Columns)+len(ts.Indexes))
+	for _, c := range ts.Columns {
+		lines = append(lines, "  "+canonicalColumn(c))
+	}
+
+	indexes := slices.Clone(ts.Indexes)
+	slices.SortFunc(indexes, func(a, b indexSummary) int {
+		switch {
+		case a.Name == b.Name:
+			return 0
+		case a.Name == "PRIMARY":
+			return -1
+		case b.Name == "PRIMARY":
+			return 1
+		default:
+			return strings.Compare(a.Name, b.Name)
+		}
+	})
+	for _, idx := range indexes {
+		lines = append(lines, "  "+canonicalIndex(idx))
+	}
+
+	foreignKeys := slices.Clone(ts.ForeignKeys)
+	slices.SortFunc(foreignKeys, func(a, b foreignKeySummary) int { return strings.Compare(a.Name, b.Name) })
+	for _, fk := range foreignKeys {
+		lines = append(lines, "  "+canonicalForeignKey(fk))
+	}
+
+	return fmt.Sprintf("CREATE TABLE %s (\n%s\n);", quoteIdent(ts.Name), strings.Join(lines, ",\n"))
+}
+
+// canonicalRoutine renders a CREATE PROCEDURE or CREATE FUNCTION statement from a routine summary.  As with SHOW
+// CREATE, characteristics are only listed when they differ from their defaults.
+func canonicalRoutine(rs routineSummary) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "CREATE %s %s(%s)\n", rs.Type, quoteIdent(rs.Name), rs.Parameters)
+
+	if rs.Type == "FUNCTION" {
+		fmt.Fprintf(&b, "    RETURNS %s\n", rs.Returns)
+	}
+	if rs.Deterministic == "YES" {
+		b.WriteString("    DETERMINISTIC\n")
+	}
+	if rs.DataAccess != "" && rs.DataAccess != "CONTAINS SQL" {
+		fmt.Fprintf(&b, "    %s\n", rs.DataAccess)
+	}
+	if rs.Security != "" && rs.Security != "DEFINER" {
+		fmt.Fprintf(&b, "    SQL SECURITY %s\n", rs.Security)
+	}
+
+	fmt.Fprintf(&b, "%s;", rs.Definition)
+
+	return b.String()
+}
+
+// canonicalTrigger renders a CREATE TRIGGER statement from a trigger summary
+func canonicalTrigger(ts triggerSummary) string {
+	return fmt.Sprintf(
+		"CREATE TRIGGER %s %s %s ON %s FOR EACH ROW %s;",
+		quoteIdent(ts.Name),
+		ts.Timing,
+		ts.Event,
+		quoteIdent(ts.Table),
+		ts.Statement,
+	)
+}
@@ -2,43 +2,40 @@ package main
 
 import (
 	"fmt"
-	"io"
 
 	"github.com/urfave/cli/v2"
 )
 
 func diffRun(cctx *cli.Context) error {
-	conns, err := preRun(cctx)
+	sources, err := preRun(cctx)
 	if err != nil {
 		return err
 	}
 
-	defer conns.Close()
+	defer sources.Close()
 
-	formatter, err := BuildFormatter(cctx)
+	pipelines, err := BuildPipelines(cctx)
 	if err != nil {
-		return fmt.Errorf("error building formatter: %w", err)
-	}
-
-	output, err := BuildOutput(cctx)
-	if err != nil {
-		return fmt.Errorf("error building output: %w", err)
+		return err
 	}
 
-	outputWriter, err := output.Writer()
+	summaries, err := summarizeSources(cctx.Context, sources)
 	if err != nil {
-		return fmt.Errorf("error opening writer for output: %w", err)
+		return fmt.Errorf("error building summaries: %w", err)
 	}
 
-	// if this is a closeable writer, queue up close.
-	if wc, ok := outputWriter.(io.Closer); ok {
-		defer func() { _ = wc.Close() }()
+	if baseline := cctx.String(flagBaseline); baseline != "" {
+		if summaries, err = summaries.WithBaseline(baseline); err != nil {
+			return err
+		}
 	}
 
-	summaries, err := summarizeConnections(cctx.Context, conns)
-	if err != nil {
-		return fmt.Errorf("error building summaries: %w", err)
+	// summarizing is by far the slowest step, so it is done once for every pipeline
+	for i, pl := range pipelines {
+		if err = pl.Run(summaries); err != nil {
+			return fmt.Errorf("error running %s pipeline %d: %w", pl.Formatter.Type(), i+1, err)
+		}
 	}
 
-	return formatter.Render(summaries, outputWriter)
+	return nil
 }
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+func fingerprintRun(cctx *cli.Context) error {
+	sources, err := preRun(cctx)
+	if err != nil {
+		return err
+	}
+
+	defer sources.Close()
+
+	summaries, err := summarizeSources(cctx.Context, sources)
+	if err != nil {
+		return fmt.Errorf("error building summaries: %w", err)
+	}
+
+	fingerprints := fingerprintConnections(summaries)
+
+	var b []byte
+	if cctx.Bool(flagPretty) {
+		b, err = json.MarshalIndent(fingerprints, "", "  ")
+	} else {
+		b, err = json.Marshal(fingerprints)
+	}
+
+	if err != nil {
+		return fmt.Errorf("error json-marshalling fingerprints: %w", err)
+	}
+
+	fmt.Println(string(b))
+
+	return nil
+}
@@ -1,37 +1,36 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
 
 	"github.com/urfave/cli/v2"
 )
 
 func summaryRun(cctx *cli.Context) error {
-	conns, err := preRun(cctx)
+	sources, err := preRun(cctx)
 	if err != nil {
 		return err
 	}
 
-	defer conns.Close()
+	defer sources.Close()
 
-	summaries, err := summarizeConnections(cctx.Context, conns)
+	formatter, err := BuildSummaryFormatter(cctx)
 	if err != nil {
-		return fmt.Errorf("error building summaries: %w", err)
+		return fmt.Errorf("error building formatter: %w", err)
 	}
 
-	var b []byte
-	if cctx.Bool(flagPretty) {
-		b, err = json.MarshalIndent(summaries, "", "  ")
-	} else {
-		b, err = json.Marshal(summaries)
+	output, err := BuildOutput(cctx)
+	if err != nil {
+		return fmt.Errorf("error building output: %w", err)
 	}
 
+	summaries, err := summarizeSources(cctx.Context, sources)
 	if err != nil {
-		return fmt.Errorf("error json-marshalling summary: %w", err)
+		return fmt.Errorf("error building summaries: %w", err)
 	}
 
-	fmt.Println(string(b))
-
-	return nil
+	return renderDatabases(output, summaries, false, func(summaries connectionSummaries, w io.Writer) error {
+		return formatter.Render(newSnapshotFile(summaries), w)
+	})
 }
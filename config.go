@@ -69,7 +69,7 @@ func parseConnConfig(in string) (connConfig, error) {
 			cc.Databases = append(cc.Databases, value)
 
 		default:
-			return connConfig{}, fmt.Errorf("uknown key %q", key)
+			return connConfig{}, fmt.Errorf("unknown key %q", key)
 		}
 	}
 
@@ -94,9 +94,6 @@ func parseConnFlags(cctx *cli.Context) ([]connConfig, error) {
 
 	// fetch flag
 	connFlags := cctx.StringSlice(flagConn)
-	if len(connFlags) == 0 {
-		return nil, errors.New("at least one connection configuration must be provided")
-	}
 
 	for _, cf := range connFlags {
 		cc, err := parseConnConfig(cf)
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type ddlTokenKind int
+
+const (
+	ddlTokenWord ddlTokenKind = iota
+	ddlTokenQuotedIdent
+	ddlTokenString
+	ddlTokenNumber
+	ddlTokenVariable
+	ddlTokenSymbol
+)
+
+// ddlToken is a single lexed SQL token.  Value contains the unquoted value for quoted identifiers and strings, and the
+// raw text for everything else.  Start and End are byte offsets into the lexed source.
+type ddlToken struct {
+	Kind  ddlTokenKind
+	Value string
+	Line  int
+	Start int
+	End   int
+}
+
+// IsWord returns true if this token is an unquoted word equal to any of the provided keywords, ignoring case
+func (t ddlToken) IsWord(kws ...string) bool {
+	if t.Kind != ddlTokenWord {
+		return false
+	}
+	for _, kw := range kws {
+		if strings.EqualFold(t.Value, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSymbol returns true if this token is the provided symbol
+func (t ddlToken) IsSymbol(sym string) bool {
+	return t.Kind == ddlTokenSymbol && t.Value == sym
+}
+
+// IsIdent returns true if this token may be used as an identifier
+func (t ddlToken) IsIdent() bool {
+	return t.Kind == ddlTokenWord || t.Kind == ddlTokenQuotedIdent
+}
+
+func (t ddlToken) String() string {
+	switch t.Kind {
+	case ddlTokenQuotedIdent:
+		return fmt.Sprintf("`%s`", t.Value)
+	case ddlTokenString:
+		return fmt.Sprintf("'%s'", t.Value)
+	default:
+		return t.Value
+	}
+}
+
+// ddlStatement is a single delimited statement within a SQL source
+type ddlStatement struct {
+	File   string
+	Src    string
+	Line   int
+	Tokens []ddlToken
+}
+
+// Text returns the source text spanning the provided token range, inclusive
+func (s ddlStatement) Text(from, to int) string {
+	if from > to || from >= len(s.Tokens) {
+		return ""
+	}
+	return s.Src[s.Tokens[from].Start:s.Tokens[to].End]
+}
+
+// splitDDL lexes the provided SQL source into statements.  It understands the client-side DELIMITER command and
+// treats the contents of MySQL executable comments ("/*!50001 ... */") as regular SQL, as both are used heavily by
+// mysqldump.
+func splitDDL(file, src string) ([]ddlStatement, error) {
+	return splitDDLWithDelimiter(file, src, ";")
+}
+
+// splitDDLWithDelimiter lexes the provided SQL source into statements using an initial delimiter.  If the delimiter
+// is empty the entire source is lexed as a single statement.
+func splitDDLWithDelimiter(file, src, delim string) ([]ddlStatement, error) {
+	var (
+		out       []ddlStatement
+		cur       []ddlToken
+		line      = 1
+		lineStart = true
+		execDepth = 0
+	)
+
+	// delimiters such as "$$" may directly follow a word, as in "END$$"
+	atDelim := func(i int) bool {
+		return delim != "" && strings.HasPrefix(src[i:], delim)
+	}
+
+	flush := func() {
+		if len(cur) > 0 {
+			out = append(out, ddlStatement{File: file, Src: src, Line: cur[0].Line, Tokens: cur})
+			cur = nil
+		}
+	}
+
+	i := 0
+	for i < len(src) {
+		c := src[i]
+
+		// the DELIMITER command must appear at the start of a line, outside of any statement
+		if lineStart && len(cur) == 0 {
+			j := i
+			for j < len(src) && (src[j] == ' ' || src[j] == '\t') {
+				j++
+			}
+			if len(src)-j > len("DELIMITER") &&
+				strings.EqualFold(src[j:j+len("DELIMITER")], "DELIMITER") &&
+				(src[j+len("DELIMITER")] == ' ' || src[j+len("DELIMITER")] == '\t') {
+				end := strings.IndexByte(src[j:], '\n')
+				if end == -1 {
+					end = len(src) - j
+				}
+				delim = strings.TrimSpace(src[j+len("DELIMITER") : j+end])
+				if delim == "" {
+					return nil, fmt.Errorf("%s:%d: DELIMITER requires a value", file, line)
+				}
+				i = j + end
+				continue
+			}
+		}
+
+		if c == '\n' {
+			line++
+			lineStart = true
+			i++
+			continue
+		}
+
+		if unicode.IsSpace(rune(c)) {
+			i++
+			continue
+		}
+
+		lineStart = false
+
+		// statement delimiter
+		if atDelim(i) {
+			flush()
+			i += len(delim)
+			continue
+		}
+
+		switch {
+		// comments
+		case strings.HasPrefix(src[i:], "/*!"):
+			i += 3
+			for i < len(src) && src[i] >= '0' && src[i] <= '9' {
+				i++
+			}
+			execDepth++
+			continue
+
+		case execDepth > 0 && strings.HasPrefix(src[i:], "*/"):
+			execDepth--
+			i += 2
+			continue
+
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end == -1 {
+				return nil, fmt.Errorf("%s:%d: unterminated comment", file, line)
+			}
+			line += strings.Count(src[i:i+2+end+2], "\n")
+			i += 2 + end + 2
+			continue
+
+		case c == '#' || (strings.HasPrefix(src[i:], "--") && (i+2 == len(src) || unicode.IsSpace(rune(src[i+2])))):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			continue
+		}
+
+		tok := ddlToken{Line: line, Start: i}
+
+		switch {
+		case c == '`':
+			v, n, err := lexQuoted(src[i:], '`')
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", file, line, err)
+			}
+			tok.Kind, tok.Value = ddlTokenQuotedIdent, v
+			i += n
+
+		case c == '\'' || c == '"':
+			v, n, err := lexQuoted(src[i:], c)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", file, line, err)
+			}
+			tok.Kind, tok.Value = ddlTokenString, v
+			line += strings.Count(src[i:i+n], "\n")
+			i += n
+
+		case c == '@':
+			j := i + 1
+			for j < len(src) && (src[j] == '@' || isIdentByte(src[j]) || src[j] == '.') {
+				j++
+			}
+			tok.Kind, tok.Value = ddlTokenVariable, src[i:j]
+			i = j
+
+		case c >= '0' && c <= '9' || (c == '.' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9'):
+			j := i
+			for j < len(src) && (isIdentByte(src[j]) || src[j] == '.') && !atDelim(j) {
+				j++
+			}
+			tok.Kind, tok.Value = ddlTokenNumber, src[i:j]
+			i = j
+
+		case isIdentByte(c):
+			j := i
+			for j < len(src) && isIdentByte(src[j]) && !atDelim(j) {
+				j++
+			}
+			tok.Kind, tok.Value = ddlTokenWord, src[i:j]
+			i = j
+
+		default:
+			tok.Kind, tok.Value = ddlTokenSymbol, string(c)
+			i++
+		}
+
+		tok.End = i
+		cur = append(cur, tok)
+	}
+
+	flush()
+
+	return out, nil
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || c >= 0x80 || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// lexQuoted reads a quoted value from the start of the input, returning the unquoted value and the number of bytes
+// consumed.  Doubled quote characters are treated as escaped quotes, as are backslash escapes within strings.
+func lexQuoted(in string, quote byte) (string, int, error) {
+	var b strings.Builder
+	for i := 1; i < len(in); i++ {
+		c := in[i]
+		switch {
+		case c == '\\' && quote != '`' && i+1 < len(in):
+			i++
+			switch in[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case '0':
+				b.WriteByte(0)
+			default:
+				b.WriteByte(in[i])
+			}
+		case c == quote && i+1 < len(in) && in[i+1] == quote:
+			b.WriteByte(quote)
+			i++
+		case c == quote:
+			return b.String(), i + 1, nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated %c quoted value", quote)
+}
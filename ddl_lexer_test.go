@@ -0,0 +1,120 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSplitDDL(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []string
+	}{
+		{
+			name: "statements",
+			src:  "CREATE TABLE a (id int);\n\nDROP TABLE b;",
+			want: []string{"CREATE TABLE a ( id int )", "DROP TABLE b"},
+		},
+		{
+			name: "comments",
+			src:  "-- a comment;\n# another;\nCREATE /* inline; */ TABLE a (id int); --\n",
+			want: []string{"CREATE TABLE a ( id int )"},
+		},
+		{
+			name: "double dash without space",
+			src:  "SELECT 1--1;",
+			want: []string{"SELECT 1 - - 1"},
+		},
+		{
+			name: "executable comments",
+			src:  "/*!40101 SET NAMES utf8mb4 */;\n/*!50001 CREATE VIEW `v` AS SELECT 1 AS `x`*/;",
+			want: []string{"SET NAMES utf8mb4", "CREATE VIEW `v` AS SELECT 1 AS `x`"},
+		},
+		{
+			name: "delimiter",
+			src:  "DELIMITER ;;\nCREATE TRIGGER t BEFORE INSERT ON a FOR EACH ROW BEGIN SET NEW.x = 1; END ;;\nDELIMITER ;\nDROP TABLE a;",
+			want: []string{
+				"CREATE TRIGGER t BEFORE INSERT ON a FOR EACH ROW BEGIN SET NEW . x = 1 ; END",
+				"DROP TABLE a",
+			},
+		},
+		{
+			name: "delimiter with indentation",
+			src:  "  DELIMITER $$\nSELECT 1; SELECT 2$$\n\tdelimiter ;\nSELECT 3;",
+			want: []string{"SELECT 1 ; SELECT 2", "SELECT 3"},
+		},
+		{
+			name: "delimiter following a word",
+			src:  "DELIMITER $$\nCREATE PROCEDURE p() BEGIN SELECT 1; END$$\nDELIMITER ;",
+			want: []string{"CREATE PROCEDURE p ( ) BEGIN SELECT 1 ; END"},
+		},
+		{
+			name: "quoted identifiers",
+			src:  "CREATE TABLE `a``b` (`semi;colon` int, `select` int);",
+			want: []string{"CREATE TABLE `a`b` ( `semi;colon` int , `select` int )"},
+		},
+		{
+			name: "strings",
+			src:  `INSERT INTO a VALUES ('x;y', "q""uote", 'it''s', 'back\'slash\n');`,
+			want: []string{"INSERT INTO a VALUES ( 'x;y' , 'q\"uote' , 'it's' , 'back'slash\n' )"},
+		},
+		{
+			name: "variables and numbers",
+			src:  "SET @OLD_SQL_MODE=@@SQL_MODE, x=1.5e3, y=.5;",
+			want: []string{"SET @OLD_SQL_MODE = @@SQL_MODE , x = 1.5e3 , y = .5"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmts, err := splitDDL("test.sql", tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := make([]string, len(stmts))
+			for i, s := range stmts {
+				toks := make([]string, len(s.Tokens))
+				for j, tok := range s.Tokens {
+					toks[j] = tok.String()
+				}
+				got[i] = strings.Join(toks, " ")
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected statements:\n%q\ngot:\n%q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSplitDDLLines(t *testing.T) {
+	src := "/* a\nmulti-line\ncomment */\nCREATE TABLE a (\n  s varchar(10) DEFAULT 'x\ny'\n);\nDROP TABLE a;"
+
+	stmts, err := splitDDL("test.sql", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stmts) != 2 || stmts[0].Line != 4 || stmts[1].Line != 8 {
+		t.Fatalf("expected statements on lines 4 and 8, got %+v", stmts)
+	}
+	if text := stmts[0].Text(0, len(stmts[0].Tokens)-1); !strings.HasPrefix(text, "CREATE TABLE a (\n") {
+		t.Errorf("expected statement text to be taken from the source, got %q", text)
+	}
+}
+
+func TestSplitDDLErrors(t *testing.T) {
+	for name, src := range map[string]string{
+		"unterminated comment":    "CREATE TABLE a (id int) /* no end",
+		"unterminated string":     "INSERT INTO a VALUES ('x);",
+		"unterminated identifier": "CREATE TABLE `a (id int);",
+		"empty delimiter":         "DELIMITER  \nSELECT 1;",
+	} {
+		if _, err := splitDDL("test.sql", src); err == nil {
+			t.Errorf("expected %s to be rejected", name)
+		}
+	}
+}
@@ -0,0 +1,1340 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ddlTypeAliases maps data type synonyms to the name MySQL reports in SHOW COLUMNS
+var ddlTypeAliases = map[string]string{
+	"integer":   "int",
+	"int4":      "int",
+	"int8":      "bigint",
+	"int1":      "tinyint",
+	"int2":      "smallint",
+	"int3":      "mediumint",
+	"middleint": "mediumint",
+	"dec":       "decimal",
+	"numeric":   "decimal",
+	"fixed":     "decimal",
+	"real":      "double",
+	"float8":    "double",
+	"float4":    "float",
+	"character": "char",
+}
+
+// ddlTypeDefaults contains the arguments MySQL reports for data types declared without any
+var ddlTypeDefaults = map[string]string{
+	"decimal": "(10,0)",
+	"char":    "(1)",
+	"binary":  "(1)",
+	"bit":     "(1)",
+}
+
+// ddlParser applies the statements of a single SQL source to a schema model
+type ddlParser struct {
+	schema *ddlSchema
+	stmt   ddlStatement
+	pos    int
+}
+
+// origin returns the location of the current token within the source
+func (p *ddlParser) origin() *sourceLocation {
+	line := p.stmt.Line
+	if p.pos < len(p.stmt.Tokens) {
+		line = p.stmt.Tokens[p.pos].Line
+	}
+	return &sourceLocation{File: p.stmt.File, Line: line}
+}
+
+func (p *ddlParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%s: %s", p.origin(), fmt.Sprintf(format, args...))
+}
+
+func (p *ddlParser) done() bool {
+	return p.pos >= len(p.stmt.Tokens)
+}
+
+func (p *ddlParser) peek() ddlToken {
+	if p.done() {
+		return ddlToken{Kind: ddlTokenSymbol}
+	}
+	return p.stmt.Tokens[p.pos]
+}
+
+func (p *ddlParser) next() ddlToken {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *ddlParser) acceptWord(kws ...string) bool {
+	if p.peek().IsWord(kws...) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// acceptWords consumes the provided sequence of keywords only if all of them are present
+func (p *ddlParser) acceptWords(kws ...string) bool {
+	for i, kw := range kws {
+		if p.pos+i >= len(p.stmt.Tokens) || !p.stmt.Tokens[p.pos+i].IsWord(kw) {
+			return false
+		}
+	}
+	p.pos += len(kws)
+	return true
+}
+
+func (p *ddlParser) acceptSymbol(sym string) bool {
+	if p.peek().IsSymbol(sym) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *ddlParser) expectWord(kws ...string) error {
+	if !p.acceptWord(kws...) {
+		return p.errorf("expected %s, found %q", strings.Join(kws, " or "), p.peek().String())
+	}
+	return nil
+}
+
+func (p *ddlParser) expectSymbol(sym string) error {
+	if !p.acceptSymbol(sym) {
+		return p.errorf("expected %q, found %q", sym, p.peek().String())
+	}
+	return nil
+}
+
+func (p *ddlParser) ident() (string, error) {
+	t := p.peek()
+	if p.done() || !t.IsIdent() {
+		return "", p.errorf("expected identifier, found %q", t.String())
+	}
+	p.pos++
+	return t.Value, nil
+}
+
+// qualifiedName reads an optionally database-qualified object name
+func (p *ddlParser) qualifiedName() (string, string, error) {
+	name, err := p.ident()
+	if err != nil {
+		return "", "", err
+	}
+	if p.acceptSymbol(".") {
+		obj, err := p.ident()
+		if err != nil {
+			return "", "", err
+		}
+		return name, obj, nil
+	}
+	return "", name, nil
+}
+
+// skipGroup skips a parenthesized group, returning the index of the closing parenthesis.  The parser must be
+// positioned on the opening parenthesis.
+func (p *ddlParser) skipGroup() (int, error) {
+	depth := 0
+	for !p.done() {
+		t := p.next()
+		switch {
+		case t.IsSymbol("("):
+			depth++
+		case t.IsSymbol(")"):
+			depth--
+			if depth == 0 {
+				return p.pos - 1, nil
+			}
+		}
+	}
+	return 0, p.errorf("unbalanced parentheses")
+}
+
+// skipDefiner skips an optional "DEFINER = user" clause
+func (p *ddlParser) skipDefiner() error {
+	if !p.acceptWord("DEFINER") {
+		return nil
+	}
+	if err := p.expectSymbol("="); err != nil {
+		return err
+	}
+	if p.acceptWord("CURRENT_USER") {
+		if p.acceptSymbol("(") {
+			return p.expectSymbol(")")
+		}
+		return nil
+	}
+	p.next()
+	if p.peek().Kind == ddlTokenVariable {
+		if p.next().Value == "@" {
+			p.next()
+		}
+	}
+	return nil
+}
+
+// identList reads a parenthesized list of key parts, ignoring prefix lengths, sort orders and expressions
+func (p *ddlParser) identList() ([]string, error) {
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+
+	out := make([]string, 0)
+	for {
+		if p.peek().IsSymbol("(") {
+			// functional key part
+			if _, err := p.skipGroup(); err != nil {
+				return nil, err
+			}
+		} else {
+			n, err := p.ident()
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, n)
+		}
+
+		// optional prefix length and ordering
+		if p.peek().IsSymbol("(") {
+			if _, err := p.skipGroup(); err != nil {
+				return nil, err
+			}
+		}
+		p.acceptWord("ASC", "DESC")
+
+		if p.acceptSymbol(")") {
+			return out, nil
+		}
+		if err := p.expectSymbol(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Apply applies a single statement to the schema model.  Statements which do not change the schema, such as SET or
+// INSERT, are ignored.
+func (p *ddlParser) Apply(stmt ddlStatement) error {
+	p.stmt, p.pos = stmt, 0
+
+	switch {
+	case p.acceptWord("USE"):
+		db, err := p.ident()
+		if err != nil {
+			return err
+		}
+		p.schema.current = db
+		return nil
+
+	case p.acceptWord("CREATE"):
+		return p.create()
+
+	case p.acceptWord("DROP"):
+		return p.drop()
+
+	case p.acceptWord("ALTER"):
+		return p.alter()
+
+	case p.acceptWord("RENAME"):
+		return p.renameTables()
+	}
+
+	return nil
+}
+
+func (p *ddlParser) create() error {
+	orReplace := p.acceptWords("OR", "REPLACE")
+
+	switch {
+	case p.acceptWord("DATABASE", "SCHEMA"):
+		p.acceptWords("IF", "NOT", "EXISTS")
+		db, err := p.ident()
+		if err != nil {
+			return err
+		}
+		p.schema.database(db)
+		return nil
+
+	case p.acceptWord("TEMPORARY"):
+		// temporary tables are never part of a schema
+		return nil
+
+	case p.acceptWord("TABLE"):
+		return p.createTable()
+
+	case p.peek().IsWord("UNIQUE", "FULLTEXT", "SPATIAL", "INDEX"):
+		return p.createIndex()
+	}
+
+	// remaining object types may be preceded by view or stored program options
+	for {
+		switch {
+		case p.acceptWord("ALGORITHM"):
+			if err := p.expectSymbol("="); err != nil {
+				return err
+			}
+			p.next()
+			continue
+		case p.acceptWords("SQL", "SECURITY"):
+			p.next()
+			continue
+		case p.peek().IsWord("DEFINER"):
+			if err := p.skipDefiner(); err != nil {
+				return err
+			}
+			continue
+		}
+		break
+	}
+
+	switch {
+	case p.acceptWord("VIEW"):
+		return p.createView(orReplace)
+	case p.acceptWord("TRIGGER"):
+		return p.createTrigger()
+	case p.acceptWord("PROCEDURE"):
+		return p.createRoutine("PROCEDURE")
+	case p.acceptWord("FUNCTION"):
+		return p.createRoutine("FUNCTION")
+	case p.acceptWord("AGGREGATE"):
+		if err := p.expectWord("FUNCTION"); err != nil {
+			return err
+		}
+		return p.createRoutine("FUNCTION")
+	}
+
+	return nil
+}
+
+func (p *ddlParser) createTable() error {
+	ifNotExists := p.acceptWords("IF", "NOT", "EXISTS")
+
+	dbName, name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+
+	db, err := p.schema.target(dbName)
+	if err != nil {
+		return p.errorf("%s", err)
+	}
+
+	if _, existing := db.findTable(name); existing != nil {
+		if ifNotExists {
+			return nil
+		}
+		return p.errorf("table %q already exists", name)
+	}
+
+	tbl := &ddlTable{Name: name, Type: tableTypeBase, Origin: p.origin()}
+
+	switch {
+	case p.acceptWord("LIKE"), p.peek().IsSymbol("(") && p.pos+1 < len(p.stmt.Tokens) && p.stmt.Tokens[p.pos+1].IsWord("LIKE"):
+		paren := p.acceptSymbol("(")
+		p.acceptWord("LIKE")
+		srcDB, srcName, err := p.qualifiedName()
+		if err != nil {
+			return err
+		}
+		sdb, err := p.schema.target(srcDB)
+		if err != nil {
+			return p.errorf("%s", err)
+		}
+		_, src := sdb.findTable(srcName)
+		if src == nil {
+			return p.errorf("table %q does not exist", srcName)
+		}
+		for _, c := range src.Columns {
+			cc := *c
+			tbl.Columns = append(tbl.Columns, &cc)
+		}
+		for _, idx := range src.Indexes {
+			ic := *idx
+			ic.Columns = slices.Clone(idx.Columns)
+			tbl.Indexes = append(tbl.Indexes, &ic)
+		}
+		if paren {
+			if err = p.expectSymbol(")"); err != nil {
+				return err
+			}
+		}
+
+	case p.acceptSymbol("("):
+		for {
+			if err = p.tableElement(tbl); err != nil {
+				return err
+			}
+			if p.acceptSymbol(")") {
+				break
+			}
+			if err = p.expectSymbol(","); err != nil {
+				return err
+			}
+		}
+
+	default:
+		return p.errorf("CREATE TABLE %q without column definitions is not supported", name)
+	}
+
+	if err = tbl.addForeignKeyIndexes(); err != nil {
+		return p.errorf("%s", err)
+	}
+
+	db.Tables = append(db.Tables, tbl)
+
+	return nil
+}
+
+// tableElement parses a single column, index, or constraint definition within CREATE TABLE
+func (p *ddlParser) tableElement(tbl *ddlTable) error {
+	t := p.peek()
+
+	if t.Kind == ddlTokenWord {
+		switch {
+		case t.IsWord("CONSTRAINT"), t.IsWord("PRIMARY", "UNIQUE", "INDEX", "KEY", "FULLTEXT", "SPATIAL", "FOREIGN", "CHECK"):
+			return p.indexDefinition(tbl)
+		}
+	}
+
+	col, idx, err := p.columnDefinition()
+	if err != nil {
+		return err
+	}
+
+	if i, _ := tbl.findColumn(col.Name); i != -1 {
+		return p.errorf("duplicate column %q in table %q", col.Name, tbl.Name)
+	}
+
+	tbl.Columns = append(tbl.Columns, col)
+
+	if idx != nil {
+		return tbl.addIndex(idx)
+	}
+
+	return nil
+}
+
+// indexDefinition parses an index, primary key, foreign key or check constraint definition
+func (p *ddlParser) indexDefinition(tbl *ddlTable) error {
+	origin := p.origin()
+
+	var constraint string
+	if p.acceptWord("CONSTRAINT") {
+		if !p.peek().IsWord("PRIMARY", "UNIQUE", "FOREIGN", "CHECK") {
+			n, err := p.ident()
+			if err != nil {
+				return err
+			}
+			constraint = n
+		}
+	}
+
+	idx := &ddlIndex{Name: constraint, Origin: origin}
+
+	switch {
+	case p.acceptWord("CHECK"):
+		return p.skipRest()
+
+	case p.acceptWord("FOREIGN"):
+		if err := p.expectWord("KEY"); err != nil {
+			return err
+		}
+		fk := &ddlForeignKey{Name: constraint, Origin: origin}
+		if !p.peek().IsSymbol("(") {
+			n, err := p.ident()
+			if err != nil {
+				return err
+			}
+			if fk.Name == "" {
+				fk.Name = n
+			}
+		}
+		fk.IndexName = fk.Name
+		cols, err := p.identList()
+		if err != nil {
+			return err
+		}
+		fk.Columns = cols
+		if err = p.expectWord("REFERENCES"); err != nil {
+			return err
+		}
+		if _, fk.RefTable, err = p.qualifiedName(); err != nil {
+			return err
+		}
+		if fk.RefColumns, err = p.identList(); err != nil {
+			return err
+		}
+		if fk.Name == "" {
+			fk.Name = fmt.Sprintf("%s_ibfk_%d", tbl.Name, len(tbl.ForeignKeys)+1)
+		}
+		tbl.ForeignKeys = append(tbl.ForeignKeys, fk)
+		return p.skipRest()
+
+	case p.acceptWord("PRIMARY"):
+		if err := p.expectWord("KEY"); err != nil {
+			return err
+		}
+		idx.Primary, idx.Unique = true, true
+
+	case p.acceptWord("UNIQUE"):
+		p.acceptWord("INDEX", "KEY")
+		idx.Unique = true
+
+	default:
+		p.acceptWord("FULLTEXT", "SPATIAL")
+		p.acceptWord("INDEX", "KEY")
+	}
+
+	if !p.peek().IsSymbol("(") && !p.peek().IsWord("USING") {
+		n, err := p.ident()
+		if err != nil {
+			return err
+		}
+		idx.Name = n
+	}
+
+	if p.acceptWord("USING") {
+		p.next()
+	}
+
+	cols, err := p.identList()
+	if err != nil {
+		return err
+	}
+	idx.Columns = cols
+
+	if err = tbl.addIndex(idx); err != nil {
+		return p.errorf("%s", err)
+	}
+
+	return p.skipRest()
+}
+
+// skipRest skips the remaining tokens of the current table element, stopping before the separating comma or the
+// closing parenthesis of the element list.
+func (p *ddlParser) skipRest() error {
+	for !p.done() {
+		t := p.peek()
+		if t.IsSymbol(",") || t.IsSymbol(")") {
+			return nil
+		}
+		if t.IsSymbol("(") {
+			if _, err := p.skipGroup(); err != nil {
+				return err
+			}
+			continue
+		}
+		p.pos++
+	}
+	return nil
+}
+
+// dataType reads a data type, returning it the way SHOW COLUMNS reports it.  Character set and collation clauses are
+// consumed and discarded.
+func (p *ddlParser) dataType() (string, error) {
+	t := p.next()
+	if t.Kind != ddlTokenWord {
+		return "", p.errorf("expected data type, found %q", t.String())
+	}
+
+	name := strings.ToLower(t.Value)
+
+	switch {
+	case name == "double" && p.acceptWord("PRECISION"):
+	case name == "national" || name == "long":
+		// "NATIONAL CHAR", "LONG VARCHAR", etc.
+		n := strings.ToLower(p.next().Value)
+		if name == "long" {
+			switch n {
+			case "varchar":
+				n = "mediumtext"
+			case "varbinary":
+				n = "mediumblob"
+			}
+		}
+		name = n
+	case name == "char" && p.acceptWord("VARYING"), name == "character" && p.acceptWord("VARYING"):
+		name = "varchar"
+	case name == "bool" || name == "boolean":
+		return "tinyint(1)", nil
+	}
+
+	if alias, ok := ddlTypeAliases[name]; ok {
+		name = alias
+	}
+
+	var b strings.Builder
+	b.WriteString(name)
+
+	if p.peek().IsSymbol("(") {
+		start := p.pos
+		end, err := p.skipGroup()
+		if err != nil {
+			return "", err
+		}
+		b.WriteString("(")
+		for i, a := range p.stmt.Tokens[start+1 : end] {
+			switch {
+			case a.IsSymbol(","):
+				b.WriteString(",")
+			case a.Kind == ddlTokenString:
+				b.WriteString("'" + strings.ReplaceAll(a.Value, "'", "''") + "'")
+			default:
+				if i > 0 && !p.stmt.Tokens[start+i].IsSymbol(",") {
+					b.WriteString(" ")
+				}
+				b.WriteString(a.Value)
+			}
+		}
+		b.WriteString(")")
+	} else if def, ok := ddlTypeDefaults[name]; ok {
+		b.WriteString(def)
+	}
+
+	for {
+		switch {
+		case p.acceptWord("UNSIGNED"):
+			b.WriteString(" unsigned")
+		case p.acceptWord("ZEROFILL"):
+			b.WriteString(" zerofill")
+		case p.acceptWord("SIGNED"), p.acceptWord("BINARY"), p.acceptWord("ASCII"), p.acceptWord("UNICODE"):
+		case p.acceptWords("CHARACTER", "SET"), p.acceptWord("CHARSET"), p.acceptWord("COLLATE"):
+			p.acceptSymbol("=")
+			p.next()
+		default:
+			return b.String(), nil
+		}
+	}
+}
+
+// columnAttributes collects the attributes of a column definition which are reported in the Default and Extra
+// fields of SHOW COLUMNS.
+type columnAttributes struct {
+	autoIncrement    bool
+	hasDefault       bool
+	defaultValue     string
+	defaultGenerated bool
+	onUpdate         string
+	generated        string
+	invisible        bool
+}
+
+func (ca columnAttributes) apply(col *columnSummary) {
+	if ca.hasDefault {
+		v := ca.defaultValue
+		col.Default = &v
+	}
+
+	extra := make([]string, 0)
+	if ca.autoIncrement {
+		extra = append(extra, "auto_increment")
+	}
+	if ca.defaultGenerated {
+		extra = append(extra, "DEFAULT_GENERATED")
+	}
+	if ca.onUpdate != "" {
+		extra = append(extra, "on update "+ca.onUpdate)
+	}
+	if ca.generated != "" {
+		extra = append(extra, ca.generated)
+	}
+	if ca.invisible {
+		extra = append(extra, "INVISIBLE")
+	}
+	col.Extra = strings.Join(extra, " ")
+}
+
+// columnDefinition parses a column definition, returning any index declared inline with the column
+func (p *ddlParser) columnDefinition() (*columnSummary, *ddlIndex, error) {
+	origin := p.origin()
+
+	name, err := p.ident()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	typ, err := p.dataType()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	col := &columnSummary{Name: name, Type: typ, Nullable: "YES", Origin: origin}
+
+	var (
+		idx   *ddlIndex
+		attrs columnAttributes
+	)
+
+	for !p.done() {
+		t := p.peek()
+		if t.IsSymbol(",") || t.IsSymbol(")") || t.IsWord("FIRST", "AFTER") {
+			break
+		}
+
+		switch {
+		case p.acceptWords("NOT", "NULL"):
+			col.Nullable = "NO"
+		case p.acceptWord("NULL"):
+			col.Nullable = "YES"
+		case p.acceptWord("DEFAULT"):
+			v, expr, isNull, err := p.defaultValue()
+			if err != nil {
+				return nil, nil, err
+			}
+			attrs.hasDefault, attrs.defaultValue, attrs.defaultGenerated = !isNull, v, expr
+		case p.acceptWords("ON", "UPDATE"):
+			v, _, _, err := p.defaultValue()
+			if err != nil {
+				return nil, nil, err
+			}
+			attrs.onUpdate = v
+		case p.acceptWord("AUTO_INCREMENT"):
+			attrs.autoIncrement = true
+		case p.acceptWords("PRIMARY", "KEY"), p.acceptWord("KEY"):
+			// a bare KEY attribute is a synonym for PRIMARY KEY within a column definition
+			idx = &ddlIndex{Primary: true, Unique: true, Columns: []string{name}, Origin: origin}
+		case p.acceptWord("UNIQUE"):
+			p.acceptWord("KEY")
+			idx = &ddlIndex{Unique: true, Columns: []string{name}, Origin: origin}
+		case p.acceptWords("GENERATED", "ALWAYS"), p.peek().IsWord("AS"):
+			if err = p.expectWord("AS"); err != nil {
+				return nil, nil, err
+			}
+			if _, err = p.skipGroup(); err != nil {
+				return nil, nil, err
+			}
+			attrs.generated = "VIRTUAL GENERATED"
+			if p.acceptWord("STORED") {
+				attrs.generated = "STORED GENERATED"
+			}
+			p.acceptWord("VIRTUAL")
+		case p.acceptWord("INVISIBLE"):
+			attrs.invisible = true
+		case p.acceptWord("VISIBLE"):
+		case p.acceptWord("COMMENT", "COLUMN_FORMAT", "STORAGE", "SRID", "ENGINE_ATTRIBUTE", "SECONDARY_ENGINE_ATTRIBUTE", "COLLATE"):
+			p.acceptSymbol("=")
+			p.next()
+		case p.acceptWord("CONSTRAINT"):
+			if !p.peek().IsWord("CHECK") {
+				p.next()
+			}
+		case p.acceptWord("CHECK", "REFERENCES"):
+			// check constraints and inline foreign keys, which MySQL parses but ignores, end the definition
+			if err = p.skipRest(); err != nil {
+				return nil, nil, err
+			}
+		default:
+			return nil, nil, p.errorf("unexpected %q in definition of column %q", t.String(), name)
+		}
+	}
+
+	attrs.apply(col)
+
+	return col, idx, nil
+}
+
+// defaultValue reads a column default, returning its value as reported by SHOW COLUMNS, whether it is an expression,
+// and whether it is NULL.
+func (p *ddlParser) defaultValue() (string, bool, bool, error) {
+	t := p.next()
+
+	switch {
+	case t.Kind == ddlTokenString:
+		return t.Value, false, false, nil
+
+	case t.IsWord("NULL"):
+		return "", false, true, nil
+
+	case t.IsWord("TRUE"):
+		return "1", false, false, nil
+
+	case t.IsWord("FALSE"):
+		return "0", false, false, nil
+
+	case t.IsSymbol("-") || t.IsSymbol("+"):
+		n := p.next()
+		if t.Value == "-" {
+			return "-" + n.Value, false, false, nil
+		}
+		return n.Value, false, false, nil
+
+	case t.IsWord("CURRENT_TIMESTAMP", "NOW", "LOCALTIME", "LOCALTIMESTAMP", "CURRENT_DATE", "CURDATE", "CURRENT_TIME", "CURTIME"):
+		v := strings.ToUpper(t.Value)
+		switch v {
+		case "NOW", "LOCALTIME", "LOCALTIMESTAMP":
+			v = "CURRENT_TIMESTAMP"
+		case "CURDATE":
+			v = "CURRENT_DATE"
+		case "CURTIME":
+			v = "CURRENT_TIME"
+		}
+		if p.peek().IsSymbol("(") {
+			start := p.pos
+			end, err := p.skipGroup()
+			if err != nil {
+				return "", false, false, err
+			}
+			if end > start+1 {
+				v += "(" + p.stmt.Text(start+1, end-1) + ")"
+			}
+		}
+		return v, true, false, nil
+
+	case t.IsSymbol("("):
+		p.pos--
+		start := p.pos
+		end, err := p.skipGroup()
+		if err != nil {
+			return "", false, false, err
+		}
+		return p.stmt.Text(start+1, end-1), true, false, nil
+
+	case t.Kind == ddlTokenWord && len(t.Value) == 1 && (t.Value[0] == 'b' || t.Value[0] == 'B' || t.Value[0] == 'x' || t.Value[0] == 'X') && p.peek().Kind == ddlTokenString:
+		// bit and hex literals
+		s := p.next()
+		return fmt.Sprintf("%s'%s'", strings.ToLower(t.Value), s.Value), false, false, nil
+
+	case t.Kind == ddlTokenNumber || t.Kind == ddlTokenWord:
+		return t.Value, false, false, nil
+	}
+
+	return "", false, false, p.errorf("unexpected default value %q", t.String())
+}
+
+func (p *ddlParser) createIndex() error {
+	idx := &ddlIndex{Origin: p.origin()}
+
+	if p.acceptWord("UNIQUE") {
+		idx.Unique = true
+	}
+	p.acceptWord("FULLTEXT", "SPATIAL")
+
+	if err := p.expectWord("INDEX"); err != nil {
+		return err
+	}
+
+	name, err := p.ident()
+	if err != nil {
+		return err
+	}
+	idx.Name = name
+
+	if p.acceptWord("USING") {
+		p.next()
+	}
+
+	if err = p.expectWord("ON"); err != nil {
+		return err
+	}
+
+	dbName, tblName, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+
+	db, err := p.schema.target(dbName)
+	if err != nil {
+		return p.errorf("%s", err)
+	}
+
+	_, tbl := db.findTable(tblName)
+	if tbl == nil {
+		return p.errorf("table %q does not exist", tblName)
+	}
+
+	if idx.Columns, err = p.identList(); err != nil {
+		return err
+	}
+
+	if err = tbl.addIndex(idx); err != nil {
+		return p.errorf("%s", err)
+	}
+
+	return nil
+}
+
+func (p *ddlParser) createView(orReplace bool) error {
+	origin := p.origin()
+
+	dbName, name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+
+	db, err := p.schema.target(dbName)
+	if err != nil {
+		return p.errorf("%s", err)
+	}
+
+	if i, existing := db.findTable(name); existing != nil {
+		if !orReplace || existing.Type != tableTypeView {
+			return p.errorf("table %q already exists", name)
+		}
+		db.Tables = slices.Delete(db.Tables, i, i+1)
+	}
+
+	var names []string
+	if p.peek().IsSymbol("(") {
+		if names, err = p.identList(); err != nil {
+			return err
+		}
+	}
+
+	if err = p.expectWord("AS"); err != nil {
+		return err
+	}
+
+	start, end := p.pos, len(p.stmt.Tokens)-1
+	for i := start; i < len(p.stmt.Tokens); i++ {
+		if p.stmt.Tokens[i].IsWord("WITH") && i+1 < len(p.stmt.Tokens) &&
+			p.stmt.Tokens[i+1].IsWord("CASCADED", "LOCAL", "CHECK") {
+			end = i - 1
+			break
+		}
+	}
+
+	view := &ddlTable{
+		Name:       name,
+		Type:       tableTypeView,
+		Definition: p.stmt.Text(start, end),
+		Columns:    p.viewColumns(db, p.stmt.Tokens[start:end+1]),
+		Origin:     origin,
+	}
+
+	for _, c := range view.Columns {
+		c.Origin = origin
+	}
+
+	if len(names) > 0 {
+		if len(names) != len(view.Columns) {
+			return p.errorf("view %q column list does not match its select list", name)
+		}
+		for i, n := range names {
+			view.Columns[i].Name = n
+		}
+	}
+
+	db.Tables = append(db.Tables, view)
+
+	return nil
+}
+
+// viewColumns derives the columns of a view from its select list.  Columns which directly reference a column of a
+// table in the FROM clause inherit its type, nullability and default; all others are left untyped.
+func (p *ddlParser) viewColumns(db *ddlDatabase, toks []ddlToken) []*columnSummary {
+	// skip to the outermost select list, which may be wrapped in parentheses
+	for len(toks) > 0 && toks[0].IsSymbol("(") {
+		toks = toks[1:]
+	}
+	if len(toks) == 0 || !toks[0].IsWord("SELECT") {
+		return nil
+	}
+	toks = toks[1:]
+	for len(toks) > 0 && toks[0].IsWord("ALL", "DISTINCT", "DISTINCTROW", "STRAIGHT_JOIN", "SQL_NO_CACHE", "SQL_CALC_FOUND_ROWS") {
+		toks = toks[1:]
+	}
+
+	// split the select list, and locate the tables in the FROM clause
+	var (
+		items  [][]ddlToken
+		cur    []ddlToken
+		depth  int
+		from   []ddlToken
+		inFrom bool
+	)
+	for i, t := range toks {
+		if inFrom {
+			from = toks[i:]
+			break
+		}
+		switch {
+		case t.IsSymbol("("):
+			depth++
+		case t.IsSymbol(")"):
+			depth--
+		}
+		if depth == 0 && t.IsSymbol(",") {
+			items, cur = append(items, cur), nil
+			continue
+		}
+		if depth == 0 && t.IsWord("FROM") {
+			inFrom = true
+			continue
+		}
+		cur = append(cur, t)
+	}
+	if len(cur) > 0 {
+		items = append(items, cur)
+	}
+
+	// map of table aliases to tables
+	aliases := make(map[string]*ddlTable)
+	var tables []*ddlTable
+	depth = 0
+	for i := 0; i < len(from); i++ {
+		t := from[i]
+		switch {
+		case t.IsSymbol("("):
+			depth++
+			continue
+		case t.IsSymbol(")"):
+			depth--
+			continue
+		case depth > 0 || !t.IsIdent():
+			continue
+		case t.IsWord("WHERE", "GROUP", "HAVING", "ORDER", "LIMIT", "UNION", "WINDOW"):
+			i = len(from)
+			continue
+		case t.IsWord("JOIN", "INNER", "LEFT", "RIGHT", "OUTER", "CROSS", "NATURAL", "STRAIGHT_JOIN", "ON", "USING", "AS"):
+			continue
+		}
+		// table references only follow the FROM keyword, a comma, or a join
+		if i > 0 && !from[i-1].IsSymbol(",") && !from[i-1].IsWord("JOIN", "STRAIGHT_JOIN") {
+			continue
+		}
+
+		name := t.Value
+		if i+2 < len(from) && from[i+1].IsSymbol(".") && from[i+2].IsIdent() {
+			name = from[i+2].Value
+			i += 2
+		}
+		_, tbl := db.findTable(name)
+		if tbl == nil {
+			continue
+		}
+		tables = append(tables, tbl)
+		aliases[strings.ToLower(name)] = tbl
+
+		alias := i + 1
+		if alias < len(from) && from[alias].IsWord("AS") {
+			alias++
+		}
+		if alias < len(from) && from[alias].IsIdent() &&
+			!from[alias].IsWord("JOIN", "INNER", "LEFT", "RIGHT", "CROSS", "NATURAL", "STRAIGHT_JOIN", "ON", "USING", "WHERE", "GROUP", "HAVING", "ORDER", "LIMIT", "UNION", "WINDOW") {
+			aliases[strings.ToLower(from[alias].Value)] = tbl
+		}
+	}
+
+	out := make([]*columnSummary, 0, len(items))
+	for _, item := range items {
+		col := &columnSummary{Nullable: "YES"}
+
+		expr := item
+		if n := len(item); n >= 2 && item[n-1].IsIdent() && item[n-2].IsWord("AS") {
+			col.Name, expr = item[n-1].Value, item[:n-2]
+		} else if n >= 2 && item[n-1].IsIdent() && (item[n-2].IsIdent() || item[n-2].IsSymbol(")")) {
+			col.Name, expr = item[n-1].Value, item[:n-1]
+		}
+
+		// resolve simple column references
+		var qualifier, ref string
+		switch {
+		case len(expr) == 1 && expr[0].IsIdent():
+			ref = expr[0].Value
+		case len(expr) == 3 && expr[0].IsIdent() && expr[1].IsSymbol(".") && expr[2].IsIdent():
+			qualifier, ref = expr[0].Value, expr[2].Value
+		case len(expr) == 5 && expr[0].IsIdent() && expr[1].IsSymbol(".") && expr[2].IsIdent() && expr[3].IsSymbol(".") && expr[4].IsIdent():
+			qualifier, ref = expr[2].Value, expr[4].Value
+		}
+
+		if col.Name == "" {
+			if ref != "" {
+				col.Name = ref
+			} else if len(expr) > 0 {
+				col.Name = p.stmt.Src[expr[0].Start:expr[len(expr)-1].End]
+			}
+		}
+
+		if ref != "" {
+			candidates := tables
+			if qualifier != "" {
+				candidates = nil
+				if tbl, ok := aliases[strings.ToLower(qualifier)]; ok {
+					candidates = []*ddlTable{tbl}
+				}
+			}
+			for _, tbl := range candidates {
+				if _, src := tbl.findColumn(ref); src != nil {
+					col.Type, col.Nullable, col.Default = src.Type, src.Nullable, src.Default
+					break
+				}
+			}
+		}
+
+		out = append(out, col)
+	}
+
+	return out
+}
+
+func (p *ddlParser) createTrigger() error {
+	p.acceptWords("IF", "NOT", "EXISTS")
+
+	dbName, name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+
+	db, err := p.schema.target(dbName)
+	if err != nil {
+		return p.errorf("%s", err)
+	}
+
+	if _, existing := db.findTrigger(name); existing != nil {
+		return p.errorf("trigger %q already exists", name)
+	}
+
+	trg := &triggerSummary{Name: name, Origin: &sourceLocation{File: p.stmt.File, Line: p.stmt.Line}}
+
+	if !p.peek().IsWord("BEFORE", "AFTER") {
+		return p.errorf("expected BEFORE or AFTER, found %q", p.peek().String())
+	}
+	trg.Timing = strings.ToUpper(p.next().Value)
+
+	if !p.peek().IsWord("INSERT", "UPDATE", "DELETE") {
+		return p.errorf("expected INSERT, UPDATE or DELETE, found %q", p.peek().String())
+	}
+	trg.Event = strings.ToUpper(p.next().Value)
+
+	if err = p.expectWord("ON"); err != nil {
+		return err
+	}
+	if _, trg.Table, err = p.qualifiedName(); err != nil {
+		return err
+	}
+	if _, tbl := db.findTable(trg.Table); tbl == nil {
+		return p.errorf("table %q does not exist", trg.Table)
+	} else {
+		trg.Table = tbl.Name
+	}
+
+	if !p.acceptWords("FOR", "EACH", "ROW") {
+		return p.errorf("expected FOR EACH ROW, found %q", p.peek().String())
+	}
+	if p.acceptWord("FOLLOWS", "PRECEDES") {
+		p.next()
+	}
+
+	if p.done() {
+		return p.errorf("trigger %q has no body", name)
+	}
+
+	trg.Statement = p.stmt.Text(p.pos, len(p.stmt.Tokens)-1)
+	db.Triggers = append(db.Triggers, trg)
+
+	return nil
+}
+
+func (p *ddlParser) createRoutine(rtype string) error {
+	p.acceptWords("IF", "NOT", "EXISTS")
+
+	dbName, name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+
+	db, err := p.schema.target(dbName)
+	if err != nil {
+		return p.errorf("%s", err)
+	}
+
+	if _, existing := db.findRoutine(rtype, name); existing != nil {
+		return p.errorf("%s %q already exists", strings.ToLower(rtype), name)
+	}
+
+	r := &routineSummary{
+		Name:          name,
+		Type:          rtype,
+		Origin:        &sourceLocation{File: p.stmt.File, Line: p.stmt.Line},
+		Deterministic: "NO",
+		DataAccess:    "CONTAINS SQL",
+		Security:      "DEFINER",
+	}
+
+	if err = p.expectSymbol("("); err != nil {
+		return err
+	}
+
+	params := make([]string, 0)
+	for !p.acceptSymbol(")") {
+		mode := ""
+		if rtype == "PROCEDURE" {
+			mode = "IN"
+			if p.peek().IsWord("IN", "OUT", "INOUT") {
+				mode = strings.ToUpper(p.next().Value)
+			}
+		}
+		pname, err := p.ident()
+		if err != nil {
+			return err
+		}
+		ptype, err := p.dataType()
+		if err != nil {
+			return err
+		}
+		param := fmt.Sprintf("%s %s", pname, ptype)
+		if mode != "" {
+			param = fmt.Sprintf("%s %s", mode, param)
+		}
+		params = append(params, param)
+		if !p.peek().IsSymbol(")") {
+			if err = p.expectSymbol(","); err != nil {
+				return err
+			}
+		}
+	}
+	r.Parameters = strings.Join(params, ", ")
+
+	if rtype == "FUNCTION" {
+		if err = p.expectWord("RETURNS"); err != nil {
+			return err
+		}
+		if r.Returns, err = p.dataType(); err != nil {
+			return err
+		}
+	}
+
+	for {
+		switch {
+		case p.acceptWord("COMMENT"):
+			p.next()
+		case p.acceptWords("LANGUAGE", "SQL"):
+		case p.acceptWords("NOT", "DETERMINISTIC"):
+			r.Deterministic = "NO"
+		case p.acceptWord("DETERMINISTIC"):
+			r.Deterministic = "YES"
+		case p.acceptWords("CONTAINS", "SQL"):
+			r.DataAccess = "CONTAINS SQL"
+		case p.acceptWords("NO", "SQL"):
+			r.DataAccess = "NO SQL"
+		case p.acceptWords("READS", "SQL", "DATA"):
+			r.DataAccess = "READS SQL DATA"
+		case p.acceptWords("MODIFIES", "SQL", "DATA"):
+			r.DataAccess = "MODIFIES SQL DATA"
+		case p.acceptWords("SQL", "SECURITY"):
+			r.Security = strings.ToUpper(p.next().Value)
+		default:
+			if p.done() {
+				return p.errorf("%s %q has no body", strings.ToLower(rtype), name)
+			}
+			r.Definition = p.stmt.Text(p.pos, len(p.stmt.Tokens)-1)
+			db.Routines = append(db.Routines, r)
+			return nil
+		}
+	}
+}
+
+func (p *ddlParser) drop() error {
+	p.acceptWord("TEMPORARY")
+
+	var kind string
+	switch {
+	case p.acceptWord("DATABASE", "SCHEMA"):
+		kind = "DATABASE"
+	case p.acceptWord("TABLE", "TABLES"):
+		kind = "TABLE"
+	case p.acceptWord("VIEW"):
+		kind = "VIEW"
+	case p.acceptWord("TRIGGER"):
+		kind = "TRIGGER"
+	case p.acceptWord("PROCEDURE"):
+		kind = "PROCEDURE"
+	case p.acceptWord("FUNCTION"):
+		kind = "FUNCTION"
+	case p.acceptWord("INDEX"):
+		return p.dropIndex()
+	default:
+		return nil
+	}
+
+	ifExists := p.acceptWords("IF", "EXISTS")
+
+	if kind == "DATABASE" {
+		name, err := p.ident()
+		if err != nil {
+			return err
+		}
+		i, _ := p.schema.findDatabase(name)
+		switch {
+		case i != -1:
+			p.schema.Databases = slices.Delete(p.schema.Databases, i, i+1)
+		case !ifExists:
+			return p.errorf("database %q does not exist", name)
+		}
+		return nil
+	}
+
+	for {
+		dbName, name, err := p.qualifiedName()
+		if err != nil {
+			return err
+		}
+
+		db, err := p.schema.target(dbName)
+		if err != nil {
+			return p.errorf("%s", err)
+		}
+
+		found := false
+		switch kind {
+		case "TABLE", "VIEW":
+			if i, tbl := db.findTable(name); tbl != nil && (kind == "TABLE") == (tbl.Type == tableTypeBase) {
+				db.dropTable(i)
+				found = true
+			}
+		case "TRIGGER":
+			if i, _ := db.findTrigger(name); i != -1 {
+				db.Triggers = slices.Delete(db.Triggers, i, i+1)
+				found = true
+			}
+		default:
+			if i, _ := db.findRoutine(kind, name); i != -1 {
+				db.Routines = slices.Delete(db.Routines, i, i+1)
+				found = true
+			}
+		}
+
+		if !found && !ifExists {
+			return p.errorf("%s %q does not exist", strings.ToLower(kind), name)
+		}
+
+		if !p.acceptSymbol(",") {
+			return nil
+		}
+	}
+}
+
+func (p *ddlParser) dropIndex() error {
+	name, err := p.ident()
+	if err != nil {
+		return err
+	}
+
+	if err = p.expectWord("ON"); err != nil {
+		return err
+	}
+
+	dbName, tblName, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+
+	db, err := p.schema.target(dbName)
+	if err != nil {
+		return p.errorf("%s", err)
+	}
+
+	_, tbl := db.findTable(tblName)
+	if tbl == nil {
+		return p.errorf("table %q does not exist", tblName)
+	}
+
+	if !tbl.dropIndex(name) {
+		return p.errorf("index %q does not exist on table %q", name, tblName)
+	}
+
+	return nil
+}
@@ -0,0 +1,382 @@
+package main
+
+import (
+	"slices"
+	"strings"
+)
+
+func (p *ddlParser) alter() error {
+	p.acceptWord("ONLINE", "OFFLINE")
+	p.acceptWord("IGNORE")
+
+	if p.acceptWord("TABLE") {
+		return p.alterTable()
+	}
+
+	// ALTER VIEW accepts the same options as CREATE VIEW
+	for {
+		switch {
+		case p.acceptWord("ALGORITHM"):
+			if err := p.expectSymbol("="); err != nil {
+				return err
+			}
+			p.next()
+			continue
+		case p.acceptWords("SQL", "SECURITY"):
+			p.next()
+			continue
+		case p.peek().IsWord("DEFINER"):
+			if err := p.skipDefiner(); err != nil {
+				return err
+			}
+			continue
+		}
+		break
+	}
+
+	if !p.acceptWord("VIEW") {
+		return nil
+	}
+
+	start := p.pos
+	dbName, name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	db, err := p.schema.target(dbName)
+	if err != nil {
+		return p.errorf("%s", err)
+	}
+	if _, view := db.findTable(name); view == nil || view.Type != tableTypeView {
+		return p.errorf("view %q does not exist", name)
+	}
+
+	p.pos = start
+	return p.createView(true)
+}
+
+func (p *ddlParser) alterTable() error {
+	dbName, name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+
+	db, err := p.schema.target(dbName)
+	if err != nil {
+		return p.errorf("%s", err)
+	}
+
+	_, tbl := db.findTable(name)
+	if tbl == nil || tbl.Type != tableTypeBase {
+		return p.errorf("table %q does not exist", name)
+	}
+
+	for !p.done() {
+		if err = p.alterSpecification(db, tbl); err != nil {
+			return err
+		}
+		if !p.done() {
+			if err = p.expectSymbol(","); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err = tbl.addForeignKeyIndexes(); err != nil {
+		return p.errorf("%s", err)
+	}
+
+	return nil
+}
+
+// alterSpecification applies a single comma-separated ALTER TABLE specification.  Specifications which do not
+// affect the summary, such as table options, are skipped.
+func (p *ddlParser) alterSpecification(db *ddlDatabase, tbl *ddlTable) error {
+	// partition maintenance never affects the summary
+	if p.peek().IsWord("ADD", "DROP") && p.pos+1 < len(p.stmt.Tokens) && p.stmt.Tokens[p.pos+1].IsWord("PARTITION") {
+		return p.skipRest()
+	}
+
+	switch {
+	case p.acceptWord("ADD"):
+		if p.peek().IsWord("CONSTRAINT", "PRIMARY", "UNIQUE", "INDEX", "KEY", "FULLTEXT", "SPATIAL", "FOREIGN", "CHECK") {
+			return p.indexDefinition(tbl)
+		}
+		p.acceptWord("COLUMN")
+		if p.acceptSymbol("(") {
+			for {
+				if err := p.tableElement(tbl); err != nil {
+					return err
+				}
+				if p.acceptSymbol(")") {
+					return nil
+				}
+				if err := p.expectSymbol(","); err != nil {
+					return err
+				}
+			}
+		}
+		return p.placeColumn(db, tbl, "", true)
+
+	case p.acceptWord("MODIFY"):
+		p.acceptWord("COLUMN")
+		name := p.peek().Value
+		return p.placeColumn(db, tbl, name, false)
+
+	case p.acceptWord("CHANGE"):
+		p.acceptWord("COLUMN")
+		name, err := p.ident()
+		if err != nil {
+			return err
+		}
+		return p.placeColumn(db, tbl, name, false)
+
+	case p.acceptWord("DROP"):
+		switch {
+		case p.acceptWords("PRIMARY", "KEY"):
+			if !tbl.dropIndex("PRIMARY") {
+				return p.errorf("table %q has no primary key", tbl.Name)
+			}
+			return nil
+		case p.acceptWords("FOREIGN", "KEY"):
+			name, err := p.ident()
+			if err != nil {
+				return err
+			}
+			n := len(tbl.ForeignKeys)
+			tbl.ForeignKeys = slices.DeleteFunc(tbl.ForeignKeys, func(fk *ddlForeignKey) bool { return strings.EqualFold(fk.Name, name) })
+			if len(tbl.ForeignKeys) == n {
+				return p.errorf("foreign key %q does not exist on table %q", name, tbl.Name)
+			}
+			return nil
+		case p.acceptWord("INDEX", "KEY"):
+			name, err := p.ident()
+			if err != nil {
+				return err
+			}
+			if !tbl.dropIndex(name) {
+				return p.errorf("index %q does not exist on table %q", name, tbl.Name)
+			}
+			return nil
+		case p.acceptWord("CHECK", "CONSTRAINT"):
+			p.next()
+			return nil
+		}
+		p.acceptWord("COLUMN")
+		name, err := p.ident()
+		if err != nil {
+			return err
+		}
+		i, _ := tbl.findColumn(name)
+		if i == -1 {
+			return p.errorf("column %q does not exist on table %q", name, tbl.Name)
+		}
+		tbl.dropColumn(i)
+		return nil
+
+	case p.acceptWord("ALTER"):
+		if p.peek().IsWord("INDEX", "CHECK", "CONSTRAINT") {
+			return p.skipRest()
+		}
+		p.acceptWord("COLUMN")
+		name, err := p.ident()
+		if err != nil {
+			return err
+		}
+		_, col := tbl.findColumn(name)
+		if col == nil {
+			return p.errorf("column %q does not exist on table %q", name, tbl.Name)
+		}
+		switch {
+		case p.acceptWords("SET", "DEFAULT"):
+			v, expr, isNull, err := p.defaultValue()
+			if err != nil {
+				return err
+			}
+			col.Default = nil
+			if !isNull {
+				col.Default = &v
+			}
+			col.Extra = strings.TrimSpace(strings.ReplaceAll(col.Extra, "DEFAULT_GENERATED", ""))
+			if expr {
+				col.Extra = strings.TrimSpace("DEFAULT_GENERATED " + col.Extra)
+			}
+		case p.acceptWords("DROP", "DEFAULT"):
+			col.Default = nil
+			col.Extra = strings.TrimSpace(strings.ReplaceAll(col.Extra, "DEFAULT_GENERATED", ""))
+		}
+		return p.skipRest()
+
+	case p.acceptWord("RENAME"):
+		switch {
+		case p.acceptWord("COLUMN"):
+			from, err := p.ident()
+			if err != nil {
+				return err
+			}
+			if err = p.expectWord("TO"); err != nil {
+				return err
+			}
+			to, err := p.ident()
+			if err != nil {
+				return err
+			}
+			_, col := tbl.findColumn(from)
+			if col == nil {
+				return p.errorf("column %q does not exist on table %q", from, tbl.Name)
+			}
+			col.Name = to
+			db.renameColumn(tbl, from, to)
+			return nil
+		case p.acceptWord("INDEX", "KEY"):
+			from, err := p.ident()
+			if err != nil {
+				return err
+			}
+			if err = p.expectWord("TO"); err != nil {
+				return err
+			}
+			to, err := p.ident()
+			if err != nil {
+				return err
+			}
+			for _, idx := range tbl.Indexes {
+				if strings.EqualFold(idx.Name, from) {
+					idx.Name = to
+					return nil
+				}
+			}
+			return p.errorf("index %q does not exist on table %q", from, tbl.Name)
+		}
+		p.acceptWord("TO", "AS")
+		dbName, name, err := p.qualifiedName()
+		if err != nil {
+			return err
+		}
+		return p.renameTable(db, tbl, dbName, name)
+	}
+
+	// table options, partitioning, ENABLE/DISABLE KEYS, etc.
+	return p.skipRest()
+}
+
+// placeColumn parses a column definition and adds it to the table, replacing the named column if one is provided.
+// The position of the column is adjusted if FIRST or AFTER is specified.
+func (p *ddlParser) placeColumn(db *ddlDatabase, tbl *ddlTable, replace string, add bool) error {
+	col, idx, err := p.columnDefinition()
+	if err != nil {
+		return err
+	}
+
+	pos := len(tbl.Columns)
+
+	if add {
+		if i, _ := tbl.findColumn(col.Name); i != -1 {
+			return p.errorf("duplicate column %q in table %q", col.Name, tbl.Name)
+		}
+	} else {
+		i, _ := tbl.findColumn(replace)
+		if i == -1 {
+			return p.errorf("column %q does not exist on table %q", replace, tbl.Name)
+		}
+		tbl.Columns = slices.Delete(tbl.Columns, i, i+1)
+		db.renameColumn(tbl, replace, col.Name)
+		pos = i
+	}
+
+	switch {
+	case p.acceptWord("FIRST"):
+		pos = 0
+	case p.acceptWord("AFTER"):
+		after, err := p.ident()
+		if err != nil {
+			return err
+		}
+		i, _ := tbl.findColumn(after)
+		if i == -1 {
+			return p.errorf("column %q does not exist on table %q", after, tbl.Name)
+		}
+		pos = i + 1
+	}
+
+	tbl.Columns = slices.Insert(tbl.Columns, pos, col)
+
+	if idx != nil {
+		if err = tbl.addIndex(idx); err != nil {
+			return p.errorf("%s", err)
+		}
+	}
+
+	return nil
+}
+
+// renameTable moves a table to a new name, and possibly a new database, along with its triggers.  Foreign keys
+// referencing the table are updated if it remains in the same database.
+func (p *ddlParser) renameTable(db *ddlDatabase, tbl *ddlTable, toDB, to string) error {
+	target, err := p.schema.target(toDB)
+	if err != nil {
+		return p.errorf("%s", err)
+	}
+
+	if _, existing := target.findTable(to); existing != nil {
+		return p.errorf("table %q already exists", to)
+	}
+
+	i, _ := db.findTable(tbl.Name)
+	db.Tables = slices.Delete(db.Tables, i, i+1)
+
+	for _, trg := range db.Triggers {
+		if strings.EqualFold(trg.Table, tbl.Name) {
+			trg.Table = to
+		}
+	}
+
+	if target == db {
+		db.renameReferences(tbl.Name, to)
+	}
+
+	tbl.Name = to
+	target.Tables = append(target.Tables, tbl)
+
+	return nil
+}
+
+// renameTables applies a RENAME TABLE statement
+func (p *ddlParser) renameTables() error {
+	if err := p.expectWord("TABLE", "TABLES"); err != nil {
+		return err
+	}
+
+	for {
+		fromDB, from, err := p.qualifiedName()
+		if err != nil {
+			return err
+		}
+		if err = p.expectWord("TO"); err != nil {
+			return err
+		}
+		toDB, to, err := p.qualifiedName()
+		if err != nil {
+			return err
+		}
+
+		db, err := p.schema.target(fromDB)
+		if err != nil {
+			return p.errorf("%s", err)
+		}
+		_, tbl := db.findTable(from)
+		if tbl == nil {
+			return p.errorf("table %q does not exist", from)
+		}
+		if toDB == "" {
+			toDB = db.Name
+		}
+		if err = p.renameTable(db, tbl, toDB, to); err != nil {
+			return err
+		}
+
+		if !p.acceptSymbol(",") {
+			return nil
+		}
+	}
+}
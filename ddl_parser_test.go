@@ -0,0 +1,256 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// parseDDLString applies the statements of src to a schema whose default database is "db", returning its summary
+func parseDDLString(t *testing.T, src string) *databaseSummary {
+	t.Helper()
+
+	stmts, err := splitDDL("test.sql", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema := newDDLSchema("db")
+	parser := &ddlParser{schema: schema}
+	for _, stmt := range stmts {
+		if err = parser.Apply(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dbs, err := schema.Summaries([]string{"db"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return dbs[0]
+}
+
+// tableShape returns a compact description of a table's columns and indexes
+func tableShape(ts tableSummary) []string {
+	out := make([]string, 0)
+	for _, c := range ts.Columns {
+		def := "NULL"
+		if c.Default != nil {
+			def = "'" + *c.Default + "'"
+		}
+		out = append(out, strings.Join([]string{"column", c.Name, c.Type, c.Nullable, c.Key, def, c.Extra}, "|"))
+	}
+	for _, idx := range ts.Indexes {
+		out = append(out, "index|"+idx.Name+"|"+idx.Description())
+	}
+	for _, fk := range ts.ForeignKeys {
+		out = append(out, "fk|"+fk.Name+"|"+fk.Description())
+	}
+	return out
+}
+
+func TestDDLParserTables(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   string
+		table string
+		want  []string
+	}{
+		{
+			name:  "inline keys",
+			src:   "CREATE TABLE t (id int unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY, email varchar(255) UNIQUE, n integer DEFAULT 0);",
+			table: "t",
+			want: []string{
+				"column|id|int unsigned|NO|PRI|NULL|auto_increment",
+				"column|email|varchar(255)|YES|UNI|NULL|",
+				"column|n|int|YES||'0'|",
+				"index|PRIMARY|PRIMARY KEY (id)",
+				"index|email|UNIQUE KEY (email)",
+			},
+		},
+		{
+			name: "table keys",
+			src: `CREATE TABLE t (
+				a int NOT NULL,
+				b int NOT NULL,
+				c char,
+				PRIMARY KEY (a, b),
+				UNIQUE KEY uq_c (c),
+				KEY (b),
+				INDEX idx_cb (c, b)
+			);`,
+			table: "t",
+			want: []string{
+				"column|a|int|NO|PRI|NULL|",
+				"column|b|int|NO|PRI|NULL|",
+				"column|c|char(1)|YES|UNI|NULL|",
+				"index|PRIMARY|PRIMARY KEY (a,b)",
+				"index|uq_c|UNIQUE KEY (c)",
+				"index|b|KEY (b)",
+				"index|idx_cb|KEY (c,b)",
+			},
+		},
+		{
+			name:  "unique not null promoted to primary",
+			src:   "CREATE TABLE t (id int NOT NULL, UNIQUE KEY u (id));",
+			table: "t",
+			want: []string{
+				"column|id|int|NO|PRI|NULL|",
+				"index|u|UNIQUE KEY (id)",
+			},
+		},
+		{
+			name: "implicit foreign key index",
+			src: `CREATE TABLE p (id int PRIMARY KEY);
+				CREATE TABLE c (
+					id int PRIMARY KEY,
+					p_id int,
+					q_id int,
+					KEY idx_q (q_id, id),
+					CONSTRAINT fk_p FOREIGN KEY (p_id) REFERENCES p (id),
+					CONSTRAINT fk_q FOREIGN KEY (q_id) REFERENCES p (id)
+				);`,
+			table: "c",
+			want: []string{
+				"column|id|int|NO|PRI|NULL|",
+				"column|p_id|int|YES|MUL|NULL|",
+				"column|q_id|int|YES|MUL|NULL|",
+				"index|PRIMARY|PRIMARY KEY (id)",
+				"index|idx_q|KEY (q_id,id)",
+				"index|fk_p|KEY (p_id)",
+				"fk|fk_p|FOREIGN KEY (p_id) REFERENCES p(id)",
+				"fk|fk_q|FOREIGN KEY (q_id) REFERENCES p(id)",
+			},
+		},
+		{
+			name: "comments",
+			src: `CREATE TABLE t ( -- the table
+				id int, # the key
+				/* a block
+				   comment */ v varchar(10) COMMENT 'not a -- comment'
+			) COMMENT='t';`,
+			table: "t",
+			want: []string{
+				"column|id|int|YES||NULL|",
+				"column|v|varchar(10)|YES||NULL|",
+			},
+		},
+		{
+			name:  "quoted identifiers",
+			src:   "CREATE TABLE `db`.`a``b` (`select` int NOT NULL, `my col` text, PRIMARY KEY (`select`));",
+			table: "a`b",
+			want: []string{
+				"column|select|int|NO|PRI|NULL|",
+				"column|my col|text|YES||NULL|",
+				"index|PRIMARY|PRIMARY KEY (select)",
+			},
+		},
+		{
+			name: "mysqldump",
+			src: `/*!40101 SET NAMES utf8mb4 */;
+				CREATE TABLE ` + "`t`" + ` (
+				  ` + "`id`" + ` bigint NOT NULL AUTO_INCREMENT,
+				  ` + "`at`" + ` timestamp NULL DEFAULT NULL ON UPDATE CURRENT_TIMESTAMP,
+				  PRIMARY KEY (` + "`id`" + `)
+				) /*!50100 TABLESPACE ` + "`innodb_system`" + ` */ ENGINE=InnoDB AUTO_INCREMENT=42;`,
+			table: "t",
+			want: []string{
+				"column|id|bigint|NO|PRI|NULL|auto_increment",
+				"column|at|timestamp|YES||NULL|on update CURRENT_TIMESTAMP",
+				"index|PRIMARY|PRIMARY KEY (id)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, ok := parseDDLString(t, tt.src).FindTable(tt.table)
+			if !ok {
+				t.Fatalf("table %q was not defined", tt.table)
+			}
+			if got := tableShape(ts); !slices.Equal(got, tt.want) {
+				t.Errorf("expected:\n%s\ngot:\n%s", strings.Join(tt.want, "\n"), strings.Join(got, "\n"))
+			}
+		})
+	}
+}
+
+func TestDDLParserDelimitedObjects(t *testing.T) {
+	ds := parseDDLString(t, `CREATE TABLE orders (id int PRIMARY KEY, total decimal(10,2));
+
+DELIMITER ;;
+CREATE DEFINER=`+"`root`@`%`"+` PROCEDURE touch(IN cid INT)
+    READS SQL DATA
+BEGIN
+  SELECT COUNT(*) FROM orders WHERE id = cid;
+END ;;
+CREATE FUNCTION dbl(x int) RETURNS int DETERMINISTIC RETURN x * 2;;
+/*!50003 CREATE*/ /*!50003 TRIGGER orders_bi BEFORE INSERT ON orders FOR EACH ROW BEGIN
+  IF NEW.total < 0 THEN SET NEW.total = 0; END IF;
+END */;;
+DELIMITER ;
+
+CREATE VIEW big_orders (order_id) AS SELECT id FROM orders WHERE total > 100 WITH CHECK OPTION;`)
+
+	p, ok := ds.FindRoutine("PROCEDURE", "touch")
+	if !ok {
+		t.Fatal("procedure was not defined")
+	}
+	if p.Parameters != "IN cid int" || p.DataAccess != "READS SQL DATA" || !strings.HasPrefix(p.Definition, "BEGIN") || !strings.HasSuffix(p.Definition, "END") {
+		t.Errorf("unexpected procedure %+v", p)
+	}
+
+	f, ok := ds.FindRoutine("FUNCTION", "dbl")
+	if !ok {
+		t.Fatal("function was not defined")
+	}
+	if f.Returns != "int" || f.Deterministic != "YES" || f.Definition != "RETURN x * 2" {
+		t.Errorf("unexpected function %+v", f)
+	}
+
+	tr, ok := ds.FindTrigger("orders_bi")
+	if !ok {
+		t.Fatal("trigger was not defined")
+	}
+	if tr.Table != "orders" || tr.Timing != "BEFORE" || tr.Event != "INSERT" || !strings.HasSuffix(tr.Statement, "END IF;\nEND") {
+		t.Errorf("unexpected trigger %+v", tr)
+	}
+
+	v, ok := ds.FindTable("big_orders")
+	if !ok {
+		t.Fatal("view was not defined")
+	}
+	if v.Type != tableTypeView || !v.Verbatim || v.Definition != "SELECT id FROM orders WHERE total > 100" {
+		t.Errorf("unexpected view %+v", v)
+	}
+	if got := tableShape(v); !slices.Equal(got, []string{"column|order_id|int|NO||NULL|"}) {
+		t.Errorf("unexpected view columns %v", got)
+	}
+}
+
+func TestDDLParserErrors(t *testing.T) {
+	for name, src := range map[string]string{
+		"duplicate table":    "CREATE TABLE t (id int); CREATE TABLE t (id int);",
+		"duplicate column":   "CREATE TABLE t (id int, id int);",
+		"view column count":  "CREATE VIEW v (a, b) AS SELECT 1;",
+		"unterminated table": "CREATE TABLE t (id int",
+	} {
+		stmts, err := splitDDL("test.sql", src)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		parser := &ddlParser{schema: newDDLSchema("db")}
+		for _, stmt := range stmts {
+			if err = parser.Apply(stmt); err != nil {
+				break
+			}
+		}
+		if err == nil {
+			t.Errorf("expected %s to be rejected", name)
+		} else if !strings.HasPrefix(err.Error(), "test.sql:1: ") {
+			t.Errorf("expected %s error to name its location, got %q", name, err)
+		}
+	}
+}
@@ -0,0 +1,383 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	tableTypeBase = "BASE TABLE"
+	tableTypeView = "VIEW"
+)
+
+type ddlIndex struct {
+	Name    string
+	Primary bool
+	Unique  bool
+	Columns []string
+	Origin  *sourceLocation
+}
+
+// ddlForeignKey is a foreign key constraint.  IndexName is the name given to the index implicitly created for the
+// constraint, and is empty if the index should be named after its first column.
+type ddlForeignKey struct {
+	Name       string
+	IndexName  string
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+	Origin     *sourceLocation
+}
+
+// ddlTable is the in-memory model of a table or view built from DDL statements.  Column keys are derived from the
+// table's indexes when the model is converted into a tableSummary.
+type ddlTable struct {
+	Name        string
+	Type        string
+	Definition  string
+	Columns     []*columnSummary
+	Indexes     []*ddlIndex
+	ForeignKeys []*ddlForeignKey
+	Origin      *sourceLocation
+}
+
+func (t *ddlTable) findColumn(name string) (int, *columnSummary) {
+	for i, c := range t.Columns {
+		if strings.EqualFold(c.Name, name) {
+			return i, c
+		}
+	}
+	return -1, nil
+}
+
+func (t *ddlTable) primaryKey() *ddlIndex {
+	for _, idx := range t.Indexes {
+		if idx.Primary {
+			return idx
+		}
+	}
+	return nil
+}
+
+// addIndex adds the provided index to the table, naming it after its first column if no name was given
+func (t *ddlTable) addIndex(idx *ddlIndex) error {
+	if idx.Primary {
+		if t.primaryKey() != nil {
+			return fmt.Errorf("table %q has multiple primary keys defined", t.Name)
+		}
+		idx.Name = "PRIMARY"
+		for _, cn := range idx.Columns {
+			if _, c := t.findColumn(cn); c != nil {
+				c.Nullable = "NO"
+			}
+		}
+	}
+
+	if idx.Name == "" && len(idx.Columns) > 0 {
+		idx.Name = idx.Columns[0]
+		for n := 2; slices.ContainsFunc(t.Indexes, func(o *ddlIndex) bool { return strings.EqualFold(o.Name, idx.Name) }); n++ {
+			idx.Name = fmt.Sprintf("%s_%d", idx.Columns[0], n)
+		}
+	}
+
+	t.Indexes = append(t.Indexes, idx)
+
+	return nil
+}
+
+// renameColumn renames a column within every index and foreign key of the table
+func (t *ddlTable) renameColumn(from, to string) {
+	for _, idx := range t.Indexes {
+		for i, cn := range idx.Columns {
+			if strings.EqualFold(cn, from) {
+				idx.Columns[i] = to
+			}
+		}
+	}
+	for _, fk := range t.ForeignKeys {
+		for i, cn := range fk.Columns {
+			if strings.EqualFold(cn, from) {
+				fk.Columns[i] = to
+			}
+		}
+	}
+}
+
+// dropColumn removes a column, removing it from every index and dropping any index left without columns
+func (t *ddlTable) dropColumn(idx int) {
+	name := t.Columns[idx].Name
+	t.Columns = slices.Delete(t.Columns, idx, idx+1)
+
+	for _, ix := range t.Indexes {
+		ix.Columns = slices.DeleteFunc(ix.Columns, func(cn string) bool { return strings.EqualFold(cn, name) })
+	}
+	t.Indexes = slices.DeleteFunc(t.Indexes, func(ix *ddlIndex) bool { return len(ix.Columns) == 0 })
+}
+
+// dropIndex removes the named index, returning false if it does not exist
+func (t *ddlTable) dropIndex(name string) bool {
+	n := len(t.Indexes)
+	t.Indexes = slices.DeleteFunc(t.Indexes, func(idx *ddlIndex) bool { return strings.EqualFold(idx.Name, name) })
+	return len(t.Indexes) != n
+}
+
+// addForeignKeyIndexes adds the index InnoDB implicitly creates for each foreign key whose columns are not already the
+// leftmost columns of an existing index.  As with InnoDB, the index remains if the foreign key is later dropped.
+func (t *ddlTable) addForeignKeyIndexes() error {
+	for _, fk := range t.ForeignKeys {
+		covered := slices.ContainsFunc(t.Indexes, func(idx *ddlIndex) bool {
+			if len(idx.Columns) < len(fk.Columns) {
+				return false
+			}
+			for i, cn := range fk.Columns {
+				if !strings.EqualFold(idx.Columns[i], cn) {
+					return false
+				}
+			}
+			return true
+		})
+		if covered {
+			continue
+		}
+		idx := &ddlIndex{Name: fk.IndexName, Columns: slices.Clone(fk.Columns), Origin: fk.Origin}
+		if err := t.addIndex(idx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// summary converts the table model into a tableSummary, deriving each column's key the same way SHOW COLUMNS does.
+func (t *ddlTable) summary() *tableSummary {
+	ts := &tableSummary{
+		Name:        t.Name,
+		Type:        t.Type,
+		Definition:  t.Definition,
+		Verbatim:    t.Type == tableTypeView,
+		Columns:     make([]columnSummary, 0, len(t.Columns)),
+		Indexes:     make([]indexSummary, 0, len(t.Indexes)),
+		ForeignKeys: make([]foreignKeySummary, 0, len(t.ForeignKeys)),
+		Origin:      t.Origin,
+	}
+
+	indexes := t.Indexes
+
+	// a unique index over a single non-null column is reported as the primary key if none was defined.
+	pk := t.primaryKey()
+	if pk == nil {
+		for _, idx := range indexes {
+			if idx.Unique && len(idx.Columns) == 1 {
+				if _, c := t.findColumn(idx.Columns[0]); c != nil && c.Nullable == "NO" {
+					pk = idx
+					break
+				}
+			}
+		}
+	}
+
+	for _, c := range t.Columns {
+		cs := *c
+		cs.Key = ""
+
+		if t.Type == tableTypeBase {
+			switch {
+			case pk != nil && slices.ContainsFunc(pk.Columns, func(cn string) bool { return strings.EqualFold(cn, c.Name) }):
+				// primary key columns are implicitly not null
+				cs.Key, cs.Nullable = "PRI", "NO"
+			case slices.ContainsFunc(indexes, func(idx *ddlIndex) bool {
+				return idx.Unique && len(idx.Columns) == 1 && strings.EqualFold(idx.Columns[0], c.Name)
+			}):
+				cs.Key = "UNI"
+			case slices.ContainsFunc(indexes, func(idx *ddlIndex) bool {
+				return len(idx.Columns) > 0 && strings.EqualFold(idx.Columns[0], c.Name)
+			}):
+				cs.Key = "MUL"
+			}
+		}
+
+		ts.Columns = append(ts.Columns, cs)
+	}
+
+	for _, idx := range indexes {
+		ts.Indexes = append(ts.Indexes, indexSummary{
+			Name:    idx.Name,
+			Unique:  idx.Unique,
+			Columns: slices.Clone(idx.Columns),
+			Origin:  idx.Origin,
+		})
+	}
+
+	for _, fk := range t.ForeignKeys {
+		ts.ForeignKeys = append(ts.ForeignKeys, foreignKeySummary{
+			Name:       fk.Name,
+			Columns:    slices.Clone(fk.Columns),
+			RefTable:   fk.RefTable,
+			RefColumns: slices.Clone(fk.RefColumns),
+			Origin:     fk.Origin,
+		})
+	}
+
+	return ts
+}
+
+type ddlDatabase struct {
+	Name     string
+	Tables   []*ddlTable
+	Routines []*routineSummary
+	Triggers []*triggerSummary
+}
+
+func (d *ddlDatabase) findTable(name string) (int, *ddlTable) {
+	for i, t := range d.Tables {
+		if strings.EqualFold(t.Name, name) {
+			return i, t
+		}
+	}
+	return -1, nil
+}
+
+func (d *ddlDatabase) findRoutine(rtype, name string) (int, *routineSummary) {
+	for i, r := range d.Routines {
+		if r.Type == rtype && strings.EqualFold(r.Name, name) {
+			return i, r
+		}
+	}
+	return -1, nil
+}
+
+func (d *ddlDatabase) findTrigger(name string) (int, *triggerSummary) {
+	for i, t := range d.Triggers {
+		if strings.EqualFold(t.Name, name) {
+			return i, t
+		}
+	}
+	return -1, nil
+}
+
+// renameColumn renames a column of the provided table within the table's indexes and foreign keys, and within every
+// foreign key referencing it.
+func (d *ddlDatabase) renameColumn(tbl *ddlTable, from, to string) {
+	tbl.renameColumn(from, to)
+	for _, t := range d.Tables {
+		for _, fk := range t.ForeignKeys {
+			if !strings.EqualFold(fk.RefTable, tbl.Name) {
+				continue
+			}
+			for i, cn := range fk.RefColumns {
+				if strings.EqualFold(cn, from) {
+					fk.RefColumns[i] = to
+				}
+			}
+		}
+	}
+}
+
+// renameReferences updates every foreign key referencing a renamed table
+func (d *ddlDatabase) renameReferences(from, to string) {
+	for _, t := range d.Tables {
+		for _, fk := range t.ForeignKeys {
+			if strings.EqualFold(fk.RefTable, from) {
+				fk.RefTable = to
+			}
+		}
+	}
+}
+
+// dropTable removes the named table along with all of its triggers
+func (d *ddlDatabase) dropTable(idx int) {
+	name := d.Tables[idx].Name
+	d.Tables = slices.Delete(d.Tables, idx, idx+1)
+	d.Triggers = slices.DeleteFunc(d.Triggers, func(t *triggerSummary) bool { return strings.EqualFold(t.Table, name) })
+}
+
+func (d *ddlDatabase) summary() *databaseSummary {
+	ds := &databaseSummary{
+		Name:     d.Name,
+		Tables:   make([]*tableSummary, 0, len(d.Tables)),
+		Routines: make([]*routineSummary, 0, len(d.Routines)),
+		Triggers: make([]*triggerSummary, 0, len(d.Triggers)),
+	}
+
+	for _, t := range d.Tables {
+		ds.Tables = append(ds.Tables, t.summary())
+	}
+	for _, r := range d.Routines {
+		rc := *r
+		ds.Routines = append(ds.Routines, &rc)
+	}
+	for _, t := range d.Triggers {
+		tc := *t
+		ds.Triggers = append(ds.Triggers, &tc)
+	}
+
+	return ds
+}
+
+// ddlSchema is an in-memory model of one or more databases, built by applying DDL statements in order.
+type ddlSchema struct {
+	Databases []*ddlDatabase
+
+	// current is the name of the database targeted by unqualified object names
+	current string
+}
+
+func newDDLSchema(defaultDB string) *ddlSchema {
+	s := &ddlSchema{current: defaultDB}
+	if defaultDB != "" {
+		s.database(defaultDB)
+	}
+	return s
+}
+
+func (s *ddlSchema) findDatabase(name string) (int, *ddlDatabase) {
+	for i, d := range s.Databases {
+		if strings.EqualFold(d.Name, name) {
+			return i, d
+		}
+	}
+	return -1, nil
+}
+
+// database returns the named database, creating it if it does not exist
+func (s *ddlSchema) database(name string) *ddlDatabase {
+	if _, d := s.findDatabase(name); d != nil {
+		return d
+	}
+	d := &ddlDatabase{Name: name}
+	s.Databases = append(s.Databases, d)
+	return d
+}
+
+// target returns the database an optionally qualified object name refers to
+func (s *ddlSchema) target(db string) (*ddlDatabase, error) {
+	if db == "" {
+		db = s.current
+	}
+	if db == "" {
+		return nil, fmt.Errorf("no database selected")
+	}
+	return s.database(db), nil
+}
+
+// Summaries returns summaries of the named databases, or of every database in the model if no names are given
+func (s *ddlSchema) Summaries(names []string) ([]*databaseSummary, error) {
+	out := make([]*databaseSummary, 0)
+
+	if len(names) == 0 {
+		for _, d := range s.Databases {
+			out = append(out, d.summary())
+		}
+		return out, nil
+	}
+
+	for _, n := range names {
+		_, d := s.findDatabase(n)
+		if d == nil {
+			return nil, fmt.Errorf("database %q is not defined", n)
+		}
+		out = append(out, d.summary())
+	}
+
+	return out, nil
+}
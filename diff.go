@@ -0,0 +1,362 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+)
+
+type changeKind string
+
+const (
+	changeAdded   changeKind = "added"
+	changeRemoved changeKind = "removed"
+	changeChanged changeKind = "changed"
+)
+
+type objectKind string
+
+const (
+	objectTable  objectKind = "table"
+	objectColumn objectKind = "column"
+	objectIndex  objectKind = "index"
+
+	objectForeignKey objectKind = "foreign_key"
+
+	objectProcedure objectKind = "procedure"
+	objectFunction  objectKind = "function"
+	objectTrigger   objectKind = "trigger"
+)
+
+// routineObjects maps the routine types reported by MySQL to the object kind used in diffs
+var routineObjects = map[string]objectKind{
+	"PROCEDURE": objectProcedure,
+	"FUNCTION":  objectFunction,
+}
+
+// objectDiff describes a single difference between a baseline database and a target database.
+//
+// Parent is the name of the enclosing object, if any (e.g. the table of a column).  Baseline and Target contain the
+// compared property values.  For added and removed objects only the side the object exists on is set, and contains a
+// short description of the object such as a table or column type.
+type objectDiff struct {
+	Kind     changeKind `json:"kind"`
+	Object   objectKind `json:"object"`
+	Parent   string     `json:"parent,omitempty"`
+	Name     string     `json:"name"`
+	Property string     `json:"property,omitempty"`
+	Baseline string     `json:"baseline,omitempty"`
+	Target   string     `json:"target,omitempty"`
+}
+
+// Path returns the dotted path of the differing object within its database
+func (od objectDiff) Path() string {
+	if od.Parent != "" {
+		return fmt.Sprintf("%s.%s", od.Parent, od.Name)
+	}
+	return od.Name
+}
+
+// TableName returns the name of the table the differing object is, or belongs to, if any
+func (od objectDiff) TableName() string {
+	switch od.Object {
+	case objectTable:
+		return od.Name
+	case objectColumn, objectIndex, objectForeignKey, objectTrigger:
+		return od.Parent
+	default:
+		return ""
+	}
+}
+
+// ColumnName returns the name of the differing column, if the object is a column
+func (od objectDiff) ColumnName() string {
+	if od.Object == objectColumn {
+		return od.Name
+	}
+	return ""
+}
+
+// String returns a single-line, human-readable description of the difference
+func (od objectDiff) String() string {
+	switch od.Kind {
+	case changeAdded:
+		return fmt.Sprintf("%s %s was added (%s)", od.Object, od.Path(), od.Target)
+	case changeRemoved:
+		return fmt.Sprintf("%s %s was removed (%s)", od.Object, od.Path(), od.Baseline)
+	default:
+		return fmt.Sprintf("%s %s %s changed from %q to %q", od.Object, od.Path(), od.Property, od.Baseline, od.Target)
+	}
+}
+
+// Present returns true if the differing object exists in the target database
+func (od objectDiff) Present() bool {
+	return od.Kind != changeRemoved
+}
+
+type databaseRef struct {
+	Connection string `json:"connection"`
+	Database   string `json:"database"`
+}
+
+func (dr databaseRef) String() string {
+	return fmt.Sprintf("`%s`.`%s`", dr.Connection, dr.Database)
+}
+
+type databaseDiff struct {
+	Baseline    databaseRef  `json:"baseline"`
+	Target      databaseRef  `json:"target"`
+	Differences []objectDiff `json:"differences"`
+}
+
+// ObjectCount returns the number of distinct objects with at least one difference
+func (dd databaseDiff) ObjectCount() int {
+	return countObjects(dd.Differences)
+}
+
+// diffGroup contains the differences affecting a single top-level object: a table along with its columns, indexes and
+// triggers, or a routine.
+type diffGroup struct {
+	Object      objectKind
+	Name        string
+	Differences []objectDiff
+}
+
+// Groups returns the differences grouped by top-level object, in the order each object first appears
+func (dd databaseDiff) Groups() []*diffGroup {
+	type groupKey struct {
+		object objectKind
+		name   string
+	}
+
+	out := make([]*diffGroup, 0)
+	idx := make(map[groupKey]*diffGroup)
+
+	for _, od := range dd.Differences {
+		key := groupKey{od.Object, od.Name}
+		if tn := od.TableName(); tn != "" {
+			key = groupKey{objectTable, tn}
+		}
+
+		g, ok := idx[key]
+		if !ok {
+			g = &diffGroup{Object: key.object, Name: key.name}
+			idx[key] = g
+			out = append(out, g)
+		}
+		g.Differences = append(g.Differences, od)
+	}
+
+	return out
+}
+
+// Counts returns the number of added, removed and changed objects
+func (dd databaseDiff) Counts() (added, removed, changed int) {
+	for _, od := range dd.Differences {
+		switch od.Kind {
+		case changeAdded:
+			added++
+		case changeRemoved:
+			removed++
+		}
+	}
+	changed = dd.ObjectCount() - added - removed
+	return
+}
+
+type diffResult struct {
+	Baseline    databaseRef     `json:"baseline"`
+	Comparisons []*databaseDiff `json:"comparisons"`
+}
+
+// buildDiff compares the first database of the first connection against every other database in the provided
+// summaries.
+func buildDiff(summaries connectionSummaries) *diffResult {
+	dbs := summaries.AllDatabases()
+
+	res := &diffResult{
+		Comparisons: make([]*databaseDiff, 0),
+	}
+
+	if len(dbs) == 0 {
+		return res
+	}
+
+	res.Baseline = dbs[0].Ref
+
+	for _, db := range dbs[1:] {
+		res.Comparisons = append(res.Comparisons, &databaseDiff{
+			Baseline:    dbs[0].Ref,
+			Target:      db.Ref,
+			Differences: compareDatabases(dbs[0].Summary, db.Summary),
+		})
+	}
+
+	return res
+}
+
+// filterDiffs returns the differences for which the provided function returns true
+func filterDiffs(diffs []objectDiff, fn func(objectDiff) bool) []objectDiff {
+	out := make([]objectDiff, 0)
+	for _, od := range diffs {
+		if fn(od) {
+			out = append(out, od)
+		}
+	}
+	return out
+}
+
+func countObjects(diffs []objectDiff) int {
+	type objectKey struct {
+		object objectKind
+		parent string
+		name   string
+	}
+
+	seen := make(map[objectKey]struct{})
+	for _, d := range diffs {
+		seen[objectKey{d.Object, d.Parent, d.Name}] = struct{}{}
+	}
+
+	return len(seen)
+}
+
+// compareDatabases returns the list of differences between the normalized forms of two database summaries.
+func compareDatabases(baseline, target *databaseSummary) []objectDiff {
+	base, tgt := normalizeDatabase(baseline), normalizeDatabase(target)
+
+	out := make([]objectDiff, 0)
+
+	for _, tn := range unionNames(base.TableNames(), tgt.TableNames()) {
+		bt, bok := base.FindTable(tn)
+		tt, tok := tgt.FindTable(tn)
+
+		switch {
+		case !tok:
+			out = append(out, objectDiff{Kind: changeRemoved, Object: objectTable, Name: tn, Baseline: bt.Type})
+		case !bok:
+			out = append(out, objectDiff{Kind: changeAdded, Object: objectTable, Name: tn, Target: tt.Type})
+		default:
+			out = append(out, compareTables(bt, tt)...)
+		}
+	}
+
+	for _, rtype := range []string{"PROCEDURE", "FUNCTION"} {
+		obj := routineObjects[rtype]
+		for _, rn := range unionNames(base.RoutineNames(rtype), tgt.RoutineNames(rtype)) {
+			br, bok := base.FindRoutine(rtype, rn)
+			tr, tok := tgt.FindRoutine(rtype, rn)
+
+			switch {
+			case !tok:
+				out = append(out, objectDiff{Kind: changeRemoved, Object: obj, Name: rn, Baseline: br.Type})
+			case !bok:
+				out = append(out, objectDiff{Kind: changeAdded, Object: obj, Name: rn, Target: tr.Type})
+			default:
+				out = append(out, compareProperties(obj, "", rn, routineProperties, br.Properties(), tr.Properties())...)
+			}
+		}
+	}
+
+	for _, tn := range unionNames(base.TriggerNames(), tgt.TriggerNames()) {
+		bt, bok := base.FindTrigger(tn)
+		tt, tok := tgt.FindTrigger(tn)
+
+		switch {
+		case !tok:
+			out = append(out, objectDiff{Kind: changeRemoved, Object: objectTrigger, Parent: bt.Table, Name: tn, Baseline: bt.Timing + " " + bt.Event})
+		case !bok:
+			out = append(out, objectDiff{Kind: changeAdded, Object: objectTrigger, Parent: tt.Table, Name: tn, Target: tt.Timing + " " + tt.Event})
+		default:
+			out = append(out, compareProperties(objectTrigger, bt.Table, tn, triggerProperties, bt.Properties(), tt.Properties())...)
+		}
+	}
+
+	return out
+}
+
+// compareProperties returns a changed diff for each of the named properties whose values differ
+func compareProperties(obj objectKind, parent, name string, props []string, base, tgt map[string]string) []objectDiff {
+	out := make([]objectDiff, 0)
+	for _, p := range props {
+		if base[p] != tgt[p] {
+			out = append(out, objectDiff{
+				Kind:     changeChanged,
+				Object:   obj,
+				Parent:   parent,
+				Name:     name,
+				Property: p,
+				Baseline: base[p],
+				Target:   tgt[p],
+			})
+		}
+	}
+	return out
+}
+
+func compareTables(base, tgt tableSummary) []objectDiff {
+	// a view definition written in DDL cannot be compared with the expanded form reported by MySQL
+	props := tableProperties
+	if base.Verbatim != tgt.Verbatim {
+		props = slices.DeleteFunc(slices.Clone(props), func(p string) bool { return p == "definition" })
+	}
+
+	out := compareProperties(objectTable, "", base.Name, props, base.Properties(), tgt.Properties())
+
+	for _, cn := range unionNames(base.ColumnNames(), tgt.ColumnNames()) {
+		bc, bok := base.FindColumn(cn)
+		tc, tok := tgt.FindColumn(cn)
+
+		switch {
+		case !tok:
+			out = append(out, objectDiff{Kind: changeRemoved, Object: objectColumn, Parent: base.Name, Name: cn, Baseline: bc.Type})
+		case !bok:
+			out = append(out, objectDiff{Kind: changeAdded, Object: objectColumn, Parent: base.Name, Name: cn, Target: tc.Type})
+		default:
+			out = append(out, compareProperties(objectColumn, base.Name, cn, columnProperties, bc.Properties(), tc.Properties())...)
+		}
+	}
+
+	// indexes and foreign keys are unknown for summaries predating their support
+	if base.Indexes != nil && tgt.Indexes != nil {
+		for _, in := range unionNames(base.IndexNames(), tgt.IndexNames()) {
+			bi, bok := base.FindIndex(in)
+			ti, tok := tgt.FindIndex(in)
+
+			switch {
+			case !tok:
+				out = append(out, objectDiff{Kind: changeRemoved, Object: objectIndex, Parent: base.Name, Name: in, Baseline: bi.Description()})
+			case !bok:
+				out = append(out, objectDiff{Kind: changeAdded, Object: objectIndex, Parent: base.Name, Name: in, Target: ti.Description()})
+			default:
+				out = append(out, compareProperties(objectIndex, base.Name, in, indexProperties, bi.Properties(), ti.Properties())...)
+			}
+		}
+	}
+
+	if base.ForeignKeys != nil && tgt.ForeignKeys != nil {
+		for _, fn := range unionNames(base.ForeignKeyNames(), tgt.ForeignKeyNames()) {
+			bf, bok := base.FindForeignKey(fn)
+			tf, tok := tgt.FindForeignKey(fn)
+
+			switch {
+			case !tok:
+				out = append(out, objectDiff{Kind: changeRemoved, Object: objectForeignKey, Parent: base.Name, Name: fn, Baseline: bf.Description()})
+			case !bok:
+				out = append(out, objectDiff{Kind: changeAdded, Object: objectForeignKey, Parent: base.Name, Name: fn, Target: tf.Description()})
+			default:
+				out = append(out, compareProperties(objectForeignKey, base.Name, fn, foreignKeyProperties, bf.Properties(), tf.Properties())...)
+			}
+		}
+	}
+
+	return out
+}
+
+// unionNames returns the sorted, de-duplicated union of the provided name lists
+func unionNames(a, b []string) []string {
+	out := make([]string, 0, len(a)+len(b))
+	out = append(out, a...)
+	out = append(out, b...)
+	slices.Sort(out)
+	return slices.Compact(out)
+}
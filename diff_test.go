@@ -0,0 +1,134 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCompareViewDefinitions(t *testing.T) {
+	view := func(def string, verbatim bool) *databaseSummary {
+		return &databaseSummary{
+			Name: "shop",
+			Tables: []*tableSummary{{
+				Name:       "active",
+				Type:       tableTypeView,
+				Definition: def,
+				Verbatim:   verbatim,
+				Columns:    []columnSummary{{Name: "id", Type: "int", Nullable: "NO"}},
+			}},
+		}
+	}
+
+	const (
+		written  = "SELECT id FROM customers WHERE status = 'active'"
+		expanded = "select `shop`.`customers`.`id` AS `id` from `shop`.`customers` where (`shop`.`customers`.`status` = 'active')"
+	)
+
+	tests := []struct {
+		name             string
+		baseline, target *databaseSummary
+		want             []string
+	}{
+		{"ddl and live", view(written, true), view(expanded, false), []string{}},
+		{"live and ddl", view(expanded, false), view(written, true), []string{}},
+		{"ddl and ddl", view(written, true), view("SELECT id FROM customers", true), []string{"table active definition changed"}},
+		{"live and live", view(expanded, false), view("select 1 AS `id`", false), []string{"table active definition changed"}},
+		{"live qualification", view(expanded, false), view("select `customers`.`id` AS `id` from `customers` where (`customers`.`status` = 'active')", false), []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make([]string, 0)
+			for _, od := range compareDatabases(tt.baseline, tt.target) {
+				got = append(got, string(od.Object)+" "+od.Name+" "+od.Property+" "+string(od.Kind))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected differences %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCompareIndexes(t *testing.T) {
+	table := func(indexes []indexSummary) *databaseSummary {
+		return &databaseSummary{Name: "shop", Tables: []*tableSummary{{Name: "t", Type: "BASE TABLE", Indexes: indexes}}}
+	}
+
+	var (
+		pk     = indexSummary{Name: "PRIMARY", Unique: true, Columns: []string{"id"}}
+		byName = indexSummary{Name: "idx_name", Columns: []string{"name", "id"}}
+	)
+
+	tests := []struct {
+		name             string
+		baseline, target []indexSummary
+		want             []string
+	}{
+		{"identical", []indexSummary{pk, byName}, []indexSummary{byName, pk}, []string{}},
+		{"added", []indexSummary{pk}, []indexSummary{pk, byName}, []string{"index t.idx_name was added (KEY (name,id))"}},
+		{"removed", []indexSummary{pk, byName}, []indexSummary{pk}, []string{"index t.idx_name was removed (KEY (name,id))"}},
+		{
+			"column order",
+			[]indexSummary{byName},
+			[]indexSummary{{Name: "idx_name", Columns: []string{"id", "name"}}},
+			[]string{`index t.idx_name columns changed from "name,id" to "id,name"`},
+		},
+		{
+			"uniqueness",
+			[]indexSummary{byName},
+			[]indexSummary{{Name: "idx_name", Unique: true, Columns: []string{"name", "id"}}},
+			[]string{`index t.idx_name unique changed from "NO" to "YES"`},
+		},
+		{"unknown baseline", nil, []indexSummary{pk}, []string{}},
+		{"unknown target", []indexSummary{pk}, nil, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make([]string, 0)
+			for _, od := range compareDatabases(table(tt.baseline), table(tt.target)) {
+				got = append(got, od.String())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected differences %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCompareForeignKeys(t *testing.T) {
+	table := func(fks []foreignKeySummary) *databaseSummary {
+		return &databaseSummary{Name: "shop", Tables: []*tableSummary{{Name: "orders", Type: "BASE TABLE", ForeignKeys: fks}}}
+	}
+
+	fk := foreignKeySummary{Name: "fk_customer", Columns: []string{"customer_id"}, RefTable: "customers", RefColumns: []string{"id"}}
+
+	tests := []struct {
+		name             string
+		baseline, target []foreignKeySummary
+		want             []string
+	}{
+		{"identical", []foreignKeySummary{fk}, []foreignKeySummary{fk}, []string{}},
+		{"added", []foreignKeySummary{}, []foreignKeySummary{fk}, []string{"foreign_key orders.fk_customer was added (FOREIGN KEY (customer_id) REFERENCES customers(id))"}},
+		{"removed", []foreignKeySummary{fk}, []foreignKeySummary{}, []string{"foreign_key orders.fk_customer was removed (FOREIGN KEY (customer_id) REFERENCES customers(id))"}},
+		{
+			"references",
+			[]foreignKeySummary{fk},
+			[]foreignKeySummary{{Name: "fk_customer", Columns: []string{"customer_id"}, RefTable: "accounts", RefColumns: []string{"user_id"}}},
+			[]string{`foreign_key orders.fk_customer references changed from "customers(id)" to "accounts(user_id)"`},
+		},
+		{"unknown", nil, []foreignKeySummary{fk}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make([]string, 0)
+			for _, od := range compareDatabases(table(tt.baseline), table(tt.target)) {
+				got = append(got, od.String())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected differences %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
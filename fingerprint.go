@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// fingerprintVersion prefixes every hashed serialization, and must be changed whenever the serialization changes so
+// that fingerprints computed by different versions never match by accident.
+const fingerprintVersion = "v1:"
+
+// canonicalEncoder writes the serialization hashed by fingerprints.  Every value is written as its length followed by
+// the value, and every list as its length followed by its items, so that no two summaries share a serialization.  A
+// nil list is written exactly as an empty one.
+type canonicalEncoder struct {
+	b bytes.Buffer
+}
+
+func newCanonicalEncoder() *canonicalEncoder {
+	e := &canonicalEncoder{}
+	e.b.WriteString(fingerprintVersion)
+	return e
+}
+
+func (e *canonicalEncoder) str(vs ...string) {
+	for _, v := range vs {
+		e.b.WriteString(strconv.Itoa(len(v)))
+		e.b.WriteByte(':')
+		e.b.WriteString(v)
+	}
+}
+
+func (e *canonicalEncoder) list(n int) {
+	e.b.WriteByte('#')
+	e.b.WriteString(strconv.Itoa(n))
+	e.b.WriteByte(';')
+}
+
+// optional writes a value which may be absent, keeping an absent value distinct from an empty one
+func (e *canonicalEncoder) optional(v *string) {
+	if v == nil {
+		e.b.WriteByte('-')
+		return
+	}
+	e.b.WriteByte('+')
+	e.str(*v)
+}
+
+// table writes the table.  A view definition written in DDL is marked, as diffs do not compare it with the expanded
+// form reported by MySQL, so that the two never share a fingerprint.
+func (e *canonicalEncoder) table(ts *tableSummary) {
+	e.str(ts.Name, ts.Type)
+	if ts.Verbatim {
+		e.b.WriteByte('!')
+	}
+	e.str(ts.Definition)
+
+	e.list(len(ts.Columns))
+	for _, c := range ts.Columns {
+		e.str(c.Name, c.Type, c.Nullable, c.Key)
+		e.optional(c.Default)
+		e.str(c.Extra)
+	}
+
+	e.list(len(ts.Indexes))
+	for _, idx := range ts.Indexes {
+		e.str(idx.Name, strconv.FormatBool(idx.Unique))
+		e.list(len(idx.Columns))
+		e.str(idx.Columns...)
+	}
+
+	e.list(len(ts.ForeignKeys))
+	for _, fk := range ts.ForeignKeys {
+		e.str(fk.Name)
+		e.list(len(fk.Columns))
+		e.str(fk.Columns...)
+		e.str(fk.RefTable)
+		e.list(len(fk.RefColumns))
+		e.str(fk.RefColumns...)
+	}
+}
+
+func (e *canonicalEncoder) routine(rs *routineSummary) {
+	e.str(rs.Name, rs.Type, rs.Parameters, rs.Returns, rs.Deterministic, rs.DataAccess, rs.Security, rs.Definition)
+}
+
+func (e *canonicalEncoder) trigger(ts *triggerSummary) {
+	e.str(ts.Name, ts.Table, ts.Timing, ts.Event, ts.Statement)
+}
+
+// database writes every object of the database, but not its name
+func (e *canonicalEncoder) database(ds *databaseSummary) {
+	e.list(len(ds.Tables))
+	for _, tbl := range ds.Tables {
+		e.table(tbl)
+	}
+	e.list(len(ds.Routines))
+	for _, r := range ds.Routines {
+		e.routine(r)
+	}
+	e.list(len(ds.Triggers))
+	for _, t := range ds.Triggers {
+		e.trigger(t)
+	}
+}
+
+// sum returns the hex-encoded SHA-256 hash of the serialization
+func (e *canonicalEncoder) sum() string {
+	sum := sha256.Sum256(e.b.Bytes())
+	return hex.EncodeToString(sum[:])
+}
+
+// fingerprintDatabase returns a hex-encoded SHA-256 hash of the normalized database summary.  The database name is
+// excluded, so identically structured databases produce identical fingerprints.
+func fingerprintDatabase(ds *databaseSummary) string {
+	e := newCanonicalEncoder()
+	e.database(normalizeDatabase(ds))
+	return e.sum()
+}
+
+// hashStrings returns a hex-encoded SHA-256 hash of the provided values
+func hashStrings(vs ...string) string {
+	e := newCanonicalEncoder()
+	e.list(len(vs))
+	e.str(vs...)
+	return e.sum()
+}
+
+type databaseFingerprint struct {
+	Name        string            `json:"name"`
+	Fingerprint string            `json:"fingerprint"`
+	Tables      map[string]string `json:"tables"`
+	Routines    map[string]string `json:"routines"`
+}
+
+type connectionFingerprint struct {
+	Label     string                 `json:"label"`
+	Address   string                 `json:"address"`
+	Databases []*databaseFingerprint `json:"databases"`
+}
+
+// fingerprintConnections computes the database, table and routine fingerprints of every summarized database.  Routines
+// are keyed by "$type $name", as procedures and functions do not share a namespace.
+func fingerprintConnections(summaries connectionSummaries) []*connectionFingerprint {
+	out := make([]*connectionFingerprint, 0)
+
+	for _, cs := range summaries {
+		cf := &connectionFingerprint{
+			Label:     cs.Label,
+			Address:   cs.Address,
+			Databases: make([]*databaseFingerprint, 0),
+		}
+
+		for _, db := range cs.Databases {
+			norm := normalizeDatabase(db)
+
+			df := &databaseFingerprint{
+				Name:        db.Name,
+				Fingerprint: fingerprintDatabase(db),
+				Tables:      make(map[string]string),
+				Routines:    make(map[string]string),
+			}
+
+			for _, tbl := range norm.Tables {
+				e := newCanonicalEncoder()
+				e.table(tbl)
+				df.Tables[tbl.Name] = e.sum()
+			}
+			for _, r := range norm.Routines {
+				e := newCanonicalEncoder()
+				e.routine(r)
+				df.Routines[r.Type+" "+r.Name] = e.sum()
+			}
+
+			cf.Databases = append(cf.Databases, df)
+		}
+
+		out = append(out, cf)
+	}
+
+	return out
+}
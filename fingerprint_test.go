@@ -0,0 +1,94 @@
+package main
+
+import (
+	"testing"
+)
+
+func fingerprintFixture() *databaseSummary {
+	def := "0"
+	return &databaseSummary{
+		Name: "shop",
+		Tables: []*tableSummary{
+			{
+				Name: "orders",
+				Type: "BASE TABLE",
+				Columns: []columnSummary{
+					{Name: "total", Type: "int", Nullable: "NO", Default: &def},
+					{Name: "id", Type: "bigint", Nullable: "NO", Key: "PRI", Extra: "auto_increment"},
+				},
+				Indexes:     []indexSummary{{Name: "PRIMARY", Unique: true, Columns: []string{"id"}}},
+				ForeignKeys: []foreignKeySummary{},
+			},
+		},
+		Routines: []*routineSummary{{Name: "dbl", Type: "FUNCTION", Returns: "int", Definition: "RETURN x * 2"}},
+		Triggers: []*triggerSummary{},
+	}
+}
+
+func TestFingerprintPinned(t *testing.T) {
+	// these hashes must only change along with fingerprintVersion
+	fps := fingerprintConnections(connectionSummaries{{Label: "a", Databases: []*databaseSummary{fingerprintFixture()}}})
+	df := fps[0].Databases[0]
+
+	for name, tt := range map[string]struct{ got, want string }{
+		"database": {df.Fingerprint, "62858ce5b8233ff7fba7b57b1c7238071a7ce1789248108c1f98e1616b07ebf1"},
+		"table":    {df.Tables["orders"], "68c9309c86c425c49a713777cfc56a2b3169ac2dda9a9f048df0e1a2cb68bb8e"},
+		"routine":  {df.Routines["FUNCTION dbl"], "07d4e935b99abe861774beb9cf57c9c76de0c31d2f500fd8840dd82e068956ad"},
+	} {
+		if tt.got != tt.want {
+			t.Errorf("expected %s fingerprint %s, got %s", name, tt.want, tt.got)
+		}
+	}
+}
+
+func TestFingerprintDatabase(t *testing.T) {
+	base := fingerprintDatabase(fingerprintFixture())
+
+	tests := []struct {
+		name   string
+		modify func(ds *databaseSummary)
+		equal  bool
+	}{
+		{"database name", func(ds *databaseSummary) { ds.Name = "other" }, true},
+		{"nil foreign keys", func(ds *databaseSummary) { ds.Tables[0].ForeignKeys = nil }, true},
+		{"nil triggers", func(ds *databaseSummary) { ds.Triggers = nil }, true},
+		{"column order", func(ds *databaseSummary) {
+			ds.Tables[0].Columns[0], ds.Tables[0].Columns[1] = ds.Tables[0].Columns[1], ds.Tables[0].Columns[0]
+		}, true},
+		{"source location", func(ds *databaseSummary) { ds.Tables[0].Origin = &sourceLocation{File: "a.sql", Line: 3} }, true},
+		{"routine whitespace", func(ds *databaseSummary) { ds.Routines[0].Definition = "RETURN  x *\n2" }, true},
+		{"null default", func(ds *databaseSummary) { ds.Tables[0].Columns[0].Default = nil }, false},
+		{"empty default", func(ds *databaseSummary) { ds.Tables[0].Columns[0].Default = new(string) }, false},
+		{"column type", func(ds *databaseSummary) { ds.Tables[0].Columns[1].Type = "int" }, false},
+		{"index columns", func(ds *databaseSummary) { ds.Tables[0].Indexes[0].Columns = []string{"id", "total"} }, false},
+		{"value boundaries", func(ds *databaseSummary) {
+			ds.Routines[0].Returns, ds.Routines[0].Definition = "intRETURN", " x * 2"
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := fingerprintFixture()
+			tt.modify(ds)
+			if got := fingerprintDatabase(ds); (got == base) != tt.equal {
+				t.Errorf("expected equal fingerprints to be %t, got %s and %s", tt.equal, base, got)
+			}
+		})
+	}
+}
+
+func TestFingerprintVerbatimViews(t *testing.T) {
+	view := func(verbatim bool) *databaseSummary {
+		return &databaseSummary{Name: "shop", Tables: []*tableSummary{
+			{Name: "v", Type: "VIEW", Definition: "select 1", Verbatim: verbatim},
+		}}
+	}
+
+	if fingerprintDatabase(view(true)) != fingerprintDatabase(view(true)) {
+		t.Error("expected identical DDL views to share a fingerprint")
+	}
+	// the diff does not compare their definitions, so they must not be reported as identical either
+	if fingerprintDatabase(view(true)) == fingerprintDatabase(view(false)) {
+		t.Error("expected a DDL view and a server view not to share a fingerprint")
+	}
+}
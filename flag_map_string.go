@@ -36,7 +36,7 @@ func (ms *MapString) Set(value string) error {
 	}
 
 	for _, s := range strings.Split(value, ",") {
-		idx := strings.Index(value, "=")
+		idx := strings.Index(s, "=")
 		if idx == -1 {
 			return fmt.Errorf("missing \"=\" value in key: %s", s)
 		}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestMapStringSet(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   MapString
+		err    bool
+	}{
+		{name: "single", values: []string{"a=1"}, want: MapString{"a": "1"}},
+		{name: "keys of different lengths", values: []string{"title=x,collapse=true"}, want: MapString{"title": "x", "collapse": "true"}},
+		{name: "value containing equals", values: []string{"q=a=b,k=v"}, want: MapString{"q": "a=b", "k": "v"}},
+		{name: "empty value", values: []string{"a="}, want: MapString{"a": ""}},
+		{name: "repeated", values: []string{"a=1", "b=2,a=3"}, want: MapString{"a": "3", "b": "2"}},
+		{name: "missing equals", values: []string{"a=1,b"}, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ms MapString
+			var err error
+			for _, v := range tt.values {
+				if err = ms.Set(v); err != nil {
+					break
+				}
+			}
+
+			if tt.err {
+				if err == nil {
+					t.Fatalf("expected error, got %v", ms)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !maps.Equal(ms, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, ms)
+			}
+		})
+	}
+}
@@ -14,6 +14,19 @@ var (
 func init() {
 	formatters = map[string]FormatConstructor{
 		FormatSimpleTable: newSimpleTableFormatter,
+		FormatMatrix:      newMatrixFormatter,
+		FormatJSON:        newJSONFormatter,
+		FormatMarkdown:    newMarkdownFormatter,
+		FormatHTML:        newHTMLFormatter,
+		FormatJUnit:       newJUnitFormatter,
+		FormatSARIF:       newSARIFFormatter,
+		FormatUnified:     newUnifiedFormatter,
+		FormatDetailTable: newDetailTableFormatter,
+		FormatDOT:         newDOTFormatter,
+		FormatMermaid:     newMermaidFormatter,
+		FormatCSV:         newCSVFormatter,
+		FormatTemplate:    newTemplateFormatter,
+		FormatOpenMetrics: newOpenMetricsFormatter,
 	}
 }
 
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	FormatCSV = "csv"
+)
+
+var _ Formatter = (*CSVFormatter)(nil)
+
+// CSVFormatter renders one row per difference, listing the differing object and property along with the value of that
+// property in every database, for import into spreadsheets.
+type CSVFormatter struct {
+	delimiter rune
+	header    bool
+}
+
+func newCSVFormatter(_ *cli.Context, cfg map[string]string) (Formatter, error) {
+	var err error
+
+	cf := CSVFormatter{
+		delimiter: ',',
+		header:    true,
+	}
+
+	if v, ok := cfg["delimiter"]; ok {
+		switch v {
+		case "tab", `\t`:
+			v = "\t"
+		}
+		r, n := utf8.DecodeRuneInString(v)
+		if n == 0 || n != len(v) || r == utf8.RuneError || r == '"' || r == '\r' || r == '\n' {
+			return nil, fmt.Errorf("flag \"delimiter\" must be a single character other than a quote or line break, saw %q", v)
+		}
+		cf.delimiter = r
+	}
+
+	if v, ok := cfg["header"]; ok {
+		if cf.header, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("error parsing flag \"header\" value %q as bool: %w", v, err)
+		}
+	}
+
+	return &cf, nil
+}
+
+func (*CSVFormatter) Type() string {
+	return FormatCSV
+}
+
+// csvMissing is the value written for databases without the differing object, distinguishing them from databases in
+// which the value is empty
+const csvMissing = "(missing)"
+
+// diffValue returns the value of the differing property within the provided database, or the short description of
+// the object for added and removed objects.  False is returned if the object does not exist in the database.
+func diffValue(ds *databaseSummary, od objectDiff) (string, bool) {
+	var (
+		desc  string
+		props map[string]string
+		ok    bool
+	)
+
+	switch od.Object {
+	case objectTable, objectColumn, objectIndex, objectForeignKey:
+		var ts tableSummary
+		if ts, ok = ds.FindTable(od.TableName()); !ok {
+			return "", false
+		}
+		switch od.Object {
+		case objectTable:
+			desc, props = ts.Type, ts.Properties()
+		case objectColumn:
+			var cs columnSummary
+			cs, ok = ts.FindColumn(od.Name)
+			desc, props = cs.Type, cs.Properties()
+		case objectIndex:
+			var is indexSummary
+			is, ok = ts.FindIndex(od.Name)
+			desc, props = is.Description(), is.Properties()
+		case objectForeignKey:
+			var fk foreignKeySummary
+			fk, ok = ts.FindForeignKey(od.Name)
+			desc, props = fk.Description(), fk.Properties()
+		}
+	case objectProcedure, objectFunction:
+		var rs routineSummary
+		rs, ok = ds.FindRoutine(strings.ToUpper(string(od.Object)), od.Name)
+		desc, props = rs.Type, rs.Properties()
+	case objectTrigger:
+		var ts triggerSummary
+		ts, ok = ds.FindTrigger(od.Name)
+		desc, props = ts.Timing+" "+ts.Event, ts.Properties()
+	}
+
+	if !ok {
+		return "", false
+	}
+	if od.Property == "" {
+		return desc, true
+	}
+	return props[od.Property], true
+}
+
+func (cf *CSVFormatter) Render(summaries connectionSummaries, sink io.Writer) error {
+	dbs := summaries.AllDatabases()
+	res := buildDiff(summaries)
+
+	// values are read from the normalized summaries, as they are what was compared
+	normalized := make([]*databaseSummary, len(dbs))
+	for i, db := range dbs {
+		normalized[i] = normalizeDatabase(db.Summary)
+	}
+
+	w := csv.NewWriter(sink)
+	w.Comma = cf.delimiter
+
+	if cf.header {
+		hdr := []string{"database", "kind", "object", "table", "column", "name", "property"}
+		for _, db := range dbs {
+			hdr = append(hdr, db.Ref.String())
+		}
+		if err := w.Write(hdr); err != nil {
+			return fmt.Errorf("error writing output: %w", err)
+		}
+	}
+
+	for _, dd := range res.Comparisons {
+		for _, od := range dd.Differences {
+			row := []string{
+				dd.Target.String(),
+				string(od.Kind),
+				string(od.Object),
+				od.TableName(),
+				od.ColumnName(),
+				od.Name,
+				od.Property,
+			}
+			for _, ds := range normalized {
+				v, ok := diffValue(ds, od)
+				if !ok {
+					v = csvMissing
+				}
+				row = append(row, v)
+			}
+			if err := w.Write(row); err != nil {
+				return fmt.Errorf("error writing output: %w", err)
+			}
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("error writing output: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestCSVFormatter(t *testing.T) {
+	summaries := fixtureSummaries(t)
+	summaries = append(summaries, ddlSummary(t, "copy", "base.sql"))
+
+	tests := []struct {
+		golden string
+		cfg    map[string]string
+	}{
+		{"csv.csv", nil},
+		{"csv-tab.tsv", map[string]string{"delimiter": "tab", "header": "false"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			assertGolden(t, tt.golden, render(t, FormatCSV, tt.cfg, summaries))
+		})
+	}
+}
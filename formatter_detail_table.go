@@ -0,0 +1,265 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	FormatDetailTable = "detail-table"
+)
+
+var _ Formatter = (*DetailTableFormatter)(nil)
+
+// DetailTableFormatter prints one table per differing table, comparing the signature of each column side by side
+// across every database.  Cells which differ from the baseline are highlighted.
+type DetailTableFormatter struct {
+	style table.Style
+	color string
+}
+
+func newDetailTableFormatter(_ *cli.Context, cfg map[string]string) (Formatter, error) {
+	dt := DetailTableFormatter{
+		style: table.StyleDefault,
+		color: colorAuto,
+	}
+
+	if v, ok := cfg["style"]; ok {
+		if dt.style, ok = styleMap[v]; !ok {
+			return nil, fmt.Errorf("unknown style %q specified, expected one of %v", v, styleKeys())
+		}
+	}
+
+	if v, ok := cfg["color"]; ok {
+		switch v {
+		case colorAuto, colorAlways, colorNever:
+			dt.color = v
+		default:
+			return nil, fmt.Errorf("unknown \"color\" value %q specified, expected one of %v", v, []string{colorAlways, colorAuto, colorNever})
+		}
+	}
+
+	return &dt, nil
+}
+
+func (*DetailTableFormatter) Type() string {
+	return FormatDetailTable
+}
+
+// columnSignature returns the type, nullability, default, extra and key of a column, e.g.
+// "int NOT NULL auto_increment [PRI]"
+func columnSignature(cs columnSummary) string {
+	sig := cs.Type + " NULL"
+	if cs.Nullable == "NO" {
+		sig = cs.Type + " NOT NULL"
+	}
+	switch {
+	case cs.Default != nil && strings.Contains(strings.ToUpper(cs.Extra), "DEFAULT_GENERATED"):
+		sig += " DEFAULT " + *cs.Default
+	case cs.Default != nil:
+		sig += " DEFAULT " + quoteString(*cs.Default)
+	}
+	if cs.Extra != "" {
+		sig += " " + cs.Extra
+	}
+	if cs.Key != "" {
+		sig += " [" + cs.Key + "]"
+	}
+	return sig
+}
+
+// highlighter returns the function used to highlight differing cells.  Colored styles highlight cells with their
+// header colors, other styles in bold yellow.  Without color, differing cells are marked with an asterisk.
+func (dt *DetailTableFormatter) highlighter(sink io.Writer) func(string) string {
+	if dt.color == colorNever || (dt.color == colorAuto && !isTerminal(sink)) {
+		return func(s string) string { return "* " + s }
+	}
+
+	colors := dt.style.Color.Header
+	if len(colors) == 0 {
+		colors = text.Colors{text.Bold, text.FgYellow}
+	}
+
+	return func(s string) string { return colors.Sprint(s) }
+}
+
+// differingTables returns the names of every table with at least one difference from the baseline, sorted by name
+func differingTables(res *diffResult) []string {
+	out := make([]string, 0)
+	for _, dd := range res.Comparisons {
+		for _, od := range dd.Differences {
+			if tn := od.TableName(); tn != "" && !slices.Contains(out, tn) {
+				out = append(out, tn)
+			}
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+// detailKeyRow is an index or foreign key row of a detail table.  signature returns the description of the key within
+// a table, which is empty if the table has no such key, and false if the table's keys are unknown.
+type detailKeyRow struct {
+	label     string
+	signature func(ts *tableSummary) (string, bool)
+}
+
+// detailKeyRows returns a row for every index, and then every foreign key, of any of the tables
+func detailKeyRows(tables []*tableSummary) []detailKeyRow {
+	indexes, foreignKeys := make([]string, 0), make([]string, 0)
+	for _, ts := range tables {
+		if ts == nil {
+			continue
+		}
+		for _, n := range ts.IndexNames() {
+			if !slices.Contains(indexes, n) {
+				indexes = append(indexes, n)
+			}
+		}
+		for _, n := range ts.ForeignKeyNames() {
+			if !slices.Contains(foreignKeys, n) {
+				foreignKeys = append(foreignKeys, n)
+			}
+		}
+	}
+
+	out := make([]detailKeyRow, 0, len(indexes)+len(foreignKeys))
+	for _, n := range indexes {
+		out = append(out, detailKeyRow{"index " + n, func(ts *tableSummary) (string, bool) {
+			is, ok := ts.FindIndex(n)
+			if !ok {
+				return "", ts.Indexes != nil
+			}
+			return is.Description(), true
+		}})
+	}
+	for _, n := range foreignKeys {
+		out = append(out, detailKeyRow{"foreign key " + n, func(ts *tableSummary) (string, bool) {
+			fk, ok := ts.FindForeignKey(n)
+			if !ok {
+				return "", ts.ForeignKeys != nil
+			}
+			return fk.Description(), true
+		}})
+	}
+	return out
+}
+
+// signatureRow returns a row of signatures, highlighting those differing from the baseline's.  Signatures of tables
+// which are nil are never highlighted.
+func (dt *DetailTableFormatter) signatureRow(name string, tables []*tableSummary, sigs []string, highlight func(string) string) table.Row {
+	row := table.Row{name}
+	for i, sig := range sigs {
+		if i > 0 && tables[i] != nil && sig != sigs[0] {
+			if sig == "" {
+				sig = "missing"
+			}
+			sig = highlight(sig)
+		}
+		row = append(row, sig)
+	}
+	return row
+}
+
+func (dt *DetailTableFormatter) Render(summaries connectionSummaries, sink io.Writer) error {
+	dbs := summaries.AllDatabases()
+	res := buildDiff(summaries)
+	highlight := dt.highlighter(sink)
+
+	var b strings.Builder
+
+	for _, tn := range differingTables(res) {
+		tables := make([]*tableSummary, len(dbs))
+
+		// list columns in the order they are defined, starting with the baseline's
+		columns := make([]string, 0)
+		for i, db := range dbs {
+			if ts, ok := db.Summary.FindTable(tn); ok {
+				tables[i] = &ts
+				for _, cn := range ts.ColumnNames() {
+					if !slices.Contains(columns, cn) {
+						columns = append(columns, cn)
+					}
+				}
+			}
+		}
+
+		tw := table.NewWriter()
+		tw.SetStyle(dt.style)
+		tw.SetTitle("table `%s`", tn)
+
+		hdr := table.Row{"Column"}
+		for _, db := range dbs {
+			hdr = append(hdr, db.Ref.String())
+		}
+		tw.AppendHeader(hdr)
+
+		// tables missing from a database are indicated in place of the column signatures
+		row := table.Row{"(table)"}
+		for i, ts := range tables {
+			v := "missing"
+			if ts != nil {
+				v = ts.Type
+			}
+			if i > 0 && ((ts == nil) != (tables[0] == nil) || (ts != nil && ts.Type != tables[0].Type)) {
+				v = highlight(v)
+			}
+			row = append(row, v)
+		}
+		tw.AppendRow(row)
+		tw.AppendSeparator()
+
+		for _, cn := range columns {
+			sigs := make([]string, len(dbs))
+			for i, ts := range tables {
+				if ts == nil {
+					continue
+				}
+				if cs, ok := ts.FindColumn(cn); ok {
+					sigs[i] = columnSignature(cs)
+				}
+			}
+
+			tw.AppendRow(dt.signatureRow(cn, tables, sigs, highlight))
+		}
+
+		// indexes and foreign keys are listed after the columns
+		keys := detailKeyRows(tables)
+		if len(keys) > 0 {
+			tw.AppendSeparator()
+		}
+		for _, kr := range keys {
+			sigs := make([]string, len(dbs))
+			known := slices.Clone(tables)
+			for i, ts := range tables {
+				if ts == nil {
+					continue
+				}
+				var ok bool
+				if sigs[i], ok = kr.signature(ts); !ok {
+					known[i] = nil
+				}
+			}
+			// nothing is highlighted when the baseline's keys are unknown
+			if tables[0] != nil && known[0] == nil {
+				clear(known)
+			}
+			tw.AppendRow(dt.signatureRow(kr.label, known, sigs, highlight))
+		}
+
+		b.WriteString(tw.Render())
+		b.WriteString("\n\n")
+	}
+
+	if _, err := sink.Write([]byte(b.String())); err != nil {
+		return fmt.Errorf("error writing output: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetailTableFormatter(t *testing.T) {
+	summaries := fixtureSummaries(t)
+
+	assertGolden(t, "detail-table.txt", render(t, FormatDetailTable, map[string]string{"color": "never"}, summaries))
+}
+
+func TestDetailTableFormatterHighlights(t *testing.T) {
+	summary := func(label, typ, key string, indexes []indexSummary) *connectionSummary {
+		return &connectionSummary{Label: label, Databases: []*databaseSummary{{
+			Name: "shop",
+			Tables: []*tableSummary{{
+				Name:    "t",
+				Type:    typ,
+				Columns: []columnSummary{{Name: "id", Type: "int", Nullable: "NO", Key: key}},
+				Indexes: indexes,
+			}},
+		}}}
+	}
+
+	pk := []indexSummary{{Name: "PRIMARY", Unique: true, Columns: []string{"id"}}}
+
+	tests := []struct {
+		name      string
+		target    *connectionSummary
+		highlight []string
+	}{
+		{"type", summary("b", tableTypeView, "PRI", pk), []string{"* VIEW"}},
+		{"key", summary("b", "BASE TABLE", "", []indexSummary{}), []string{"* int NOT NULL", "* missing"}},
+		{"unknown indexes", summary("b", "BASE TABLE", "", nil), []string{"* int NOT NULL"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := string(render(t, FormatDetailTable, map[string]string{"color": "never"}, connectionSummaries{summary("a", "BASE TABLE", "PRI", pk), tt.target}))
+
+			if got := strings.Count(out, "* "); got != len(tt.highlight) {
+				t.Errorf("expected %d highlighted cells, got %d:\n%s", len(tt.highlight), got, out)
+			}
+			for _, h := range tt.highlight {
+				if !strings.Contains(out, h) {
+					t.Errorf("expected %q to be highlighted:\n%s", h, out)
+				}
+			}
+		})
+	}
+}
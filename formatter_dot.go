@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	FormatDOT = "dot"
+)
+
+// dotRankdirs lists the layout directions supported by Graphviz
+var dotRankdirs = []string{"TB", "LR", "BT", "RL"}
+
+var _ Formatter = (*DOTFormatter)(nil)
+
+// DOTFormatter renders the schema as a Graphviz entity-relationship diagram, with tables as nodes and foreign keys as
+// edges.  When databases are compared, one graph is rendered per comparison with differing elements color-coded.
+type DOTFormatter struct {
+	rankdir string
+}
+
+func newDOTFormatter(_ *cli.Context, cfg map[string]string) (Formatter, error) {
+	df := DOTFormatter{
+		rankdir: "LR",
+	}
+
+	if v, ok := cfg["rankdir"]; ok {
+		df.rankdir = strings.ToUpper(v)
+		if !slices.Contains(dotRankdirs, df.rankdir) {
+			return nil, fmt.Errorf("unknown \"rankdir\" value %q specified, expected one of %v", v, dotRankdirs)
+		}
+	}
+
+	return &df, nil
+}
+
+func (*DOTFormatter) Type() string {
+	return FormatDOT
+}
+
+// dotID quotes a value for use as a DOT identifier
+func dotID(v string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v) + `"`
+}
+
+// dotText escapes a value for use within an HTML-like label, struck through if the element was removed
+func dotText(v string, state changeKind) string {
+	if v == "" {
+		return ""
+	}
+	v = html.EscapeString(v)
+	if state == changeRemoved {
+		v = "<s>" + v + "</s>"
+	}
+	if c, ok := erdColors[state]; ok {
+		v = fmt.Sprintf(`<font color="%s">%s</font>`, c, v)
+	}
+	return v
+}
+
+// dotLabel renders the HTML-like label of a table.  Each column's row is a port, named after its position, for edges
+// to attach to.
+func dotLabel(et *erdTable) string {
+	var b strings.Builder
+
+	b.WriteString(`<table border="0" cellborder="1" cellspacing="0" cellpadding="4">`)
+
+	header := fmt.Sprintf(`<b>%s</b>`, html.EscapeString(et.Name))
+	if et.State == changeRemoved {
+		header = "<s>" + header + "</s>"
+	}
+	bg := "#e1e4e8"
+	if c, ok := erdColors[et.State]; ok {
+		bg = c
+		header = `<font color="#ffffff">` + header + "</font>"
+	}
+	fmt.Fprintf(&b, `<tr><td colspan="3" bgcolor="%s">%s</td></tr>`, bg, header)
+
+	for i, ec := range et.Columns {
+		fmt.Fprintf(
+			&b,
+			`<tr><td align="left">%s</td><td align="left" port="c%d">%s</td><td align="left">%s</td></tr>`,
+			dotText(strings.Join(ec.Keys, ","), ec.State),
+			i,
+			dotText(ec.Name, ec.State),
+			dotText(ec.Type, ec.State),
+		)
+	}
+
+	b.WriteString("</table>")
+
+	return b.String()
+}
+
+// dotPort returns the port of the first of the provided columns, or an empty string if the table has no such column
+func dotPort(et *erdTable, columns []string) string {
+	if len(columns) == 0 {
+		return ""
+	}
+	if i := et.ColumnIndex(columns[0]); i != -1 {
+		return fmt.Sprintf(":c%d", i)
+	}
+	return ""
+}
+
+func (df *DOTFormatter) Render(summaries connectionSummaries, sink io.Writer) error {
+	var b strings.Builder
+
+	for _, d := range buildERDiagrams(summaries) {
+		fmt.Fprintf(&b, "digraph %s {\n", dotID(d.Title))
+		fmt.Fprintf(&b, "  graph [rankdir=%s, label=%s, labelloc=t, fontname=\"Helvetica\"];\n", df.rankdir, dotID(d.Title))
+		b.WriteString("  node [shape=plain, fontname=\"Helvetica\", fontsize=10];\n")
+		b.WriteString("  edge [fontname=\"Helvetica\", fontsize=9, dir=both];\n")
+
+		if d.Diff {
+			b.WriteString("  \"(legend)\" [label=<<table border=\"0\" cellborder=\"1\" cellspacing=\"0\" cellpadding=\"4\">")
+			for _, state := range []changeKind{changeAdded, changeRemoved, changeChanged} {
+				fmt.Fprintf(&b, "<tr><td>%s</td></tr>", dotText(string(state), state))
+			}
+			b.WriteString("</table>>];\n")
+		}
+
+		for _, et := range d.Tables {
+			fmt.Fprintf(&b, "  %s [label=<%s>];\n", dotID(et.Name), dotLabel(et))
+		}
+
+		for _, ee := range d.Edges {
+			attrs := []string{
+				"label=" + dotID(ee.Name),
+				"arrowtail=crowodot",
+				"arrowhead=teetee",
+			}
+			if ee.Optional {
+				attrs[2] = "arrowhead=teeodot"
+			}
+			if c, ok := erdColors[ee.State]; ok {
+				attrs = append(attrs, fmt.Sprintf("color=%q", c), fmt.Sprintf("fontcolor=%q", c))
+			}
+			if ee.State == changeRemoved {
+				attrs = append(attrs, "style=dashed")
+			}
+
+			fmt.Fprintf(
+				&b,
+				"  %s%s -> %s%s [%s];\n",
+				dotID(ee.Table),
+				dotPort(d.FindTable(ee.Table), ee.Columns),
+				dotID(ee.RefTable),
+				dotPort(d.FindTable(ee.RefTable), ee.RefColumns),
+				strings.Join(attrs, ", "),
+			)
+		}
+
+		b.WriteString("}\n")
+	}
+
+	if _, err := sink.Write([]byte(b.String())); err != nil {
+		return fmt.Errorf("error writing output: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestDOTFormatter(t *testing.T) {
+	summaries := fixtureSummaries(t)
+
+	tests := []struct {
+		golden    string
+		cfg       map[string]string
+		summaries connectionSummaries
+	}{
+		{"dot.gv", nil, summaries},
+		{"dot-single.gv", map[string]string{"rankdir": "TB"}, summaries[:1]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			assertGolden(t, tt.golden, render(t, FormatDOT, tt.cfg, tt.summaries))
+		})
+	}
+}
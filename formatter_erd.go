@@ -0,0 +1,208 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+)
+
+// erdColors maps the state of a diagram element to the color it is drawn in.  Unchanged elements have no state.
+var erdColors = map[changeKind]string{
+	changeAdded:   "#2da44e",
+	changeRemoved: "#cf222e",
+	changeChanged: "#bf8700",
+}
+
+// erdColumn is a single column of an entity.  Keys lists the key kinds the column is part of, e.g. "PK" and "FK".
+type erdColumn struct {
+	Name     string
+	Type     string
+	Keys     []string
+	Nullable bool
+	State    changeKind
+}
+
+// erdTable is a single entity of an entity-relationship diagram
+type erdTable struct {
+	Name    string
+	State   changeKind
+	Columns []erdColumn
+}
+
+// ColumnIndex returns the position of the named column, or -1 if the table has no such column
+func (et *erdTable) ColumnIndex(name string) int {
+	return slices.IndexFunc(et.Columns, func(ec erdColumn) bool { return ec.Name == name })
+}
+
+// erdEdge is a foreign key relationship from the referencing table to the referenced table.  Optional is true if any
+// referencing column accepts NULL, in which case a row may reference nothing.
+type erdEdge struct {
+	Name       string
+	Table      string
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+	Optional   bool
+	State      changeKind
+}
+
+// erDiagram is the entity-relationship diagram of a single database, or of the union of the baseline and a compared
+// database with the state of each element relative to the baseline.
+type erDiagram struct {
+	Title  string
+	Diff   bool
+	Tables []*erdTable
+	Edges  []erdEdge
+}
+
+// FindTable returns the named entity, or nil if the diagram has no such entity
+func (d *erDiagram) FindTable(name string) *erdTable {
+	for _, et := range d.Tables {
+		if et.Name == name {
+			return et
+		}
+	}
+	return nil
+}
+
+// buildERDiagrams returns a diagram of the only database if a single database was summarized, otherwise one diagram
+// per comparison against the baseline.
+func buildERDiagrams(summaries connectionSummaries) []*erDiagram {
+	dbs := summaries.AllDatabases()
+
+	switch len(dbs) {
+	case 0:
+		return nil
+	case 1:
+		return []*erDiagram{newERDiagram(dbs[0].Ref.String(), dbs[0].Summary, dbs[0].Summary, nil)}
+	}
+
+	res := buildDiff(summaries)
+	out := make([]*erDiagram, 0, len(res.Comparisons))
+	for i, dd := range res.Comparisons {
+		d := newERDiagram(fmt.Sprintf("%s vs %s", dd.Baseline, dd.Target), dbs[0].Summary, dbs[i+1].Summary, dd.Differences)
+		d.Diff = true
+		out = append(out, d)
+	}
+	return out
+}
+
+// newERDiagram builds a diagram of the base tables of both databases, preferring the target's definition of tables
+// present in both.  Views are not part of the diagram.
+func newERDiagram(title string, base, target *databaseSummary, diffs []objectDiff) *erDiagram {
+	type childKey struct {
+		table string
+		name  string
+	}
+
+	tableStates := make(map[string]changeKind)
+	columnStates := make(map[childKey]changeKind)
+	edgeStates := make(map[childKey]changeKind)
+
+	for _, od := range diffs {
+		switch od.Object {
+		case objectTable:
+			tableStates[od.Name] = od.Kind
+			continue
+		case objectColumn:
+			columnStates[childKey{od.Parent, od.Name}] = od.Kind
+		case objectForeignKey:
+			edgeStates[childKey{od.Parent, od.Name}] = od.Kind
+		}
+
+		// any difference within a table marks the table itself as changed
+		if tn := od.TableName(); tn != "" && tableStates[tn] == "" {
+			tableStates[tn] = changeChanged
+		}
+	}
+
+	d := &erDiagram{Title: title}
+
+	names := unionNames(base.TableNames(), target.TableNames())
+	for _, tn := range names {
+		bt, bok := base.FindTable(tn)
+		tt, tok := target.FindTable(tn)
+
+		ts := tt
+		if !tok {
+			ts = bt
+		}
+		if ts.Type == tableTypeView {
+			continue
+		}
+
+		et := &erdTable{Name: tn, State: tableStates[tn]}
+
+		// removed foreign keys are listed after the target's foreign keys
+		foreignKeys := slices.Clone(ts.ForeignKeys)
+		if bok && tok {
+			for _, fk := range bt.ForeignKeys {
+				if _, ok := tt.FindForeignKey(fk.Name); !ok {
+					foreignKeys = append(foreignKeys, fk)
+				}
+			}
+		}
+
+		// removed columns are listed after the target's columns
+		columns := slices.Clone(ts.Columns)
+		if bok && tok {
+			for _, cs := range bt.Columns {
+				if _, ok := tt.FindColumn(cs.Name); !ok {
+					columns = append(columns, cs)
+				}
+			}
+		}
+
+		for _, cs := range columns {
+			ec := erdColumn{
+				Name:     cs.Name,
+				Type:     cs.Type,
+				Nullable: cs.Nullable != "NO",
+				State:    columnStates[childKey{tn, cs.Name}],
+			}
+
+			// every column of an added or removed table shares its state
+			if et.State == changeAdded || et.State == changeRemoved {
+				ec.State = et.State
+			}
+
+			switch cs.Key {
+			case "PRI":
+				ec.Keys = append(ec.Keys, "PK")
+			case "UNI":
+				ec.Keys = append(ec.Keys, "UK")
+			}
+			if slices.ContainsFunc(foreignKeys, func(fk foreignKeySummary) bool { return slices.Contains(fk.Columns, cs.Name) }) {
+				ec.Keys = append(ec.Keys, "FK")
+			}
+
+			et.Columns = append(et.Columns, ec)
+		}
+
+		d.Tables = append(d.Tables, et)
+
+		for _, fk := range foreignKeys {
+			ee := erdEdge{
+				Name:       fk.Name,
+				Table:      tn,
+				Columns:    fk.Columns,
+				RefTable:   fk.RefTable,
+				RefColumns: fk.RefColumns,
+				State:      edgeStates[childKey{tn, fk.Name}],
+			}
+			if et.State == changeAdded || et.State == changeRemoved {
+				ee.State = et.State
+			}
+			for _, cn := range fk.Columns {
+				if i := et.ColumnIndex(cn); i != -1 && et.Columns[i].Nullable {
+					ee.Optional = true
+				}
+			}
+			d.Edges = append(d.Edges, ee)
+		}
+	}
+
+	// relationships with tables outside of the diagram, such as those in other databases, cannot be drawn
+	d.Edges = slices.DeleteFunc(d.Edges, func(ee erdEdge) bool { return d.FindTable(ee.RefTable) == nil })
+
+	return d
+}
@@ -0,0 +1,504 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	FormatHTML = "html"
+)
+
+var _ Formatter = (*HTMLFormatter)(nil)
+
+// HTMLFormatter renders a self-contained HTML report comparing every database against the baseline, intended to be
+// written to a file and opened in a browser.
+type HTMLFormatter struct {
+	title string
+}
+
+func newHTMLFormatter(_ *cli.Context, cfg map[string]string) (Formatter, error) {
+	hf := HTMLFormatter{
+		title: "Schema Diff",
+	}
+
+	if v, ok := cfg["title"]; ok {
+		hf.title = v
+	}
+
+	return &hf, nil
+}
+
+func (*HTMLFormatter) Type() string {
+	return FormatHTML
+}
+
+// htmlCell is the state of a single object within a single database.  State is one of "baseline", "same", "added",
+// "removed", "changed" or "absent".
+type htmlCell struct {
+	State string
+	Value string
+	Title string
+}
+
+type htmlRow struct {
+	Object objectKind
+	Name   string
+	Cells  []htmlCell
+}
+
+type htmlObject struct {
+	ID      string
+	Object  objectKind
+	Name    string
+	Differs bool
+	Cells   []htmlCell
+	Details []htmlRow
+}
+
+type htmlComparison struct {
+	Target                  databaseRef
+	Added, Removed, Changed int
+}
+
+type htmlReport struct {
+	Title       string
+	Baseline    databaseRef
+	Databases   []databaseRef
+	Comparisons []htmlComparison
+	Objects     []*htmlObject
+}
+
+// htmlKey identifies an object within a database.  Triggers are keyed without their table, so a trigger moved between
+// tables is reported as a single changed object.
+type htmlKey struct {
+	object objectKind
+	parent string
+	name   string
+}
+
+func newHTMLKey(od objectDiff) htmlKey {
+	if od.Object == objectTrigger {
+		return htmlKey{object: od.Object, name: od.Name}
+	}
+	return htmlKey{object: od.Object, parent: od.Parent, name: od.Name}
+}
+
+// htmlDescriber returns the displayed value and tooltip of an object within a database, and whether it exists
+type htmlDescriber func(*databaseSummary) (string, string, bool)
+
+func describeProperties(props []string, values map[string]string) string {
+	out := make([]string, 0, len(props))
+	for _, p := range props {
+		if v := values[p]; v != "" {
+			out = append(out, fmt.Sprintf("%s: %s", p, v))
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+func describeTable(name string) htmlDescriber {
+	return func(ds *databaseSummary) (string, string, bool) {
+		ts, ok := ds.FindTable(name)
+		return ts.Type, describeProperties(tableProperties, ts.Properties()), ok
+	}
+}
+
+func describeColumn(table, name string) htmlDescriber {
+	return func(ds *databaseSummary) (string, string, bool) {
+		ts, ok := ds.FindTable(table)
+		if !ok {
+			return "", "", false
+		}
+		cs, ok := ts.FindColumn(name)
+		return cs.Type, describeProperties(columnProperties, cs.Properties()), ok
+	}
+}
+
+func describeIndex(table, name string) htmlDescriber {
+	return func(ds *databaseSummary) (string, string, bool) {
+		ts, ok := ds.FindTable(table)
+		if !ok {
+			return "", "", false
+		}
+		is, ok := ts.FindIndex(name)
+		return is.Description(), describeProperties(indexProperties, is.Properties()), ok
+	}
+}
+
+func describeForeignKey(table, name string) htmlDescriber {
+	return func(ds *databaseSummary) (string, string, bool) {
+		ts, ok := ds.FindTable(table)
+		if !ok {
+			return "", "", false
+		}
+		fk, ok := ts.FindForeignKey(name)
+		return fk.Description(), describeProperties(foreignKeyProperties, fk.Properties()), ok
+	}
+}
+
+func describeRoutine(rtype, name string) htmlDescriber {
+	return func(ds *databaseSummary) (string, string, bool) {
+		rs, ok := ds.FindRoutine(rtype, name)
+		return rs.Returns, describeProperties(routineProperties, rs.Properties()), ok
+	}
+}
+
+func describeTrigger(name string) htmlDescriber {
+	return func(ds *databaseSummary) (string, string, bool) {
+		ts, ok := ds.FindTrigger(name)
+		return strings.TrimSpace(ts.Timing + " " + ts.Event), describeProperties(triggerProperties, ts.Properties()), ok
+	}
+}
+
+// htmlReportBuilder computes the state of each object in each database from the diff against the baseline
+type htmlReportBuilder struct {
+	dbs   []summaryDatabase
+	diffs []map[htmlKey][]objectDiff
+}
+
+func newHTMLReportBuilder(dbs []summaryDatabase, res *diffResult) *htmlReportBuilder {
+	rb := &htmlReportBuilder{
+		dbs:   dbs,
+		diffs: make([]map[htmlKey][]objectDiff, len(res.Comparisons)),
+	}
+
+	for i, dd := range res.Comparisons {
+		rb.diffs[i] = make(map[htmlKey][]objectDiff)
+		for _, od := range dd.Differences {
+			k := newHTMLKey(od)
+			rb.diffs[i][k] = append(rb.diffs[i][k], od)
+		}
+	}
+
+	return rb
+}
+
+func (rb *htmlReportBuilder) cells(key htmlKey, describe htmlDescriber) []htmlCell {
+	out := make([]htmlCell, len(rb.dbs))
+
+	for i, db := range rb.dbs {
+		value, title, ok := describe(db.Summary)
+
+		if i == 0 {
+			out[i] = htmlCell{State: "absent"}
+			if ok {
+				out[i] = htmlCell{State: "baseline", Value: value, Title: title}
+			}
+			continue
+		}
+
+		diffs := rb.diffs[i-1][key]
+		switch {
+		case len(diffs) == 0 && ok:
+			out[i] = htmlCell{State: "same", Value: value, Title: title}
+		case len(diffs) == 0:
+			out[i] = htmlCell{State: "absent"}
+		case diffs[0].Kind == changeAdded:
+			out[i] = htmlCell{State: string(changeAdded), Value: value, Title: title}
+		case diffs[0].Kind == changeRemoved:
+			out[i] = htmlCell{State: string(changeRemoved), Title: fmt.Sprintf("missing, baseline has %s", diffs[0].Baseline)}
+		default:
+			changes := make([]string, 0, len(diffs))
+			for _, od := range diffs {
+				changes = append(changes, fmt.Sprintf("%s: %s → %s", od.Property, od.Baseline, od.Target))
+			}
+			out[i] = htmlCell{State: string(changeChanged), Value: value, Title: strings.Join(changes, "\n")}
+		}
+	}
+
+	return out
+}
+
+func htmlDiffers(cells []htmlCell) bool {
+	for _, c := range cells {
+		switch c.State {
+		case string(changeAdded), string(changeRemoved), string(changeChanged):
+			return true
+		}
+	}
+	return false
+}
+
+// unionOf returns the sorted union of the names returned by fn for every database
+func (rb *htmlReportBuilder) unionOf(fn func(*databaseSummary) []string) []string {
+	out := make([]string, 0)
+	for _, db := range rb.dbs {
+		out = unionNames(out, fn(db.Summary))
+	}
+	return out
+}
+
+func (rb *htmlReportBuilder) tableObject(name string) *htmlObject {
+	ho := &htmlObject{
+		Object: objectTable,
+		Name:   name,
+		Cells:  rb.cells(htmlKey{object: objectTable, name: name}, describeTable(name)),
+	}
+
+	columns := rb.unionOf(func(ds *databaseSummary) []string {
+		ts, _ := ds.FindTable(name)
+		return ts.ColumnNames()
+	})
+	for _, cn := range columns {
+		ho.Details = append(ho.Details, htmlRow{
+			Object: objectColumn,
+			Name:   cn,
+			Cells:  rb.cells(htmlKey{object: objectColumn, parent: name, name: cn}, describeColumn(name, cn)),
+		})
+	}
+
+	indexes := rb.unionOf(func(ds *databaseSummary) []string {
+		ts, _ := ds.FindTable(name)
+		return ts.IndexNames()
+	})
+	for _, in := range indexes {
+		ho.Details = append(ho.Details, htmlRow{
+			Object: objectIndex,
+			Name:   in,
+			Cells:  rb.cells(htmlKey{object: objectIndex, parent: name, name: in}, describeIndex(name, in)),
+		})
+	}
+
+	foreignKeys := rb.unionOf(func(ds *databaseSummary) []string {
+		ts, _ := ds.FindTable(name)
+		return ts.ForeignKeyNames()
+	})
+	for _, fn := range foreignKeys {
+		ho.Details = append(ho.Details, htmlRow{
+			Object: objectForeignKey,
+			Name:   fn,
+			Cells:  rb.cells(htmlKey{object: objectForeignKey, parent: name, name: fn}, describeForeignKey(name, fn)),
+		})
+	}
+
+	triggers := rb.unionOf(func(ds *databaseSummary) []string {
+		out := make([]string, 0)
+		for _, t := range ds.Triggers {
+			if t.Table == name {
+				out = append(out, t.Name)
+			}
+		}
+		return out
+	})
+	for _, tn := range triggers {
+		ho.Details = append(ho.Details, htmlRow{
+			Object: objectTrigger,
+			Name:   tn,
+			Cells:  rb.cells(htmlKey{object: objectTrigger, name: tn}, describeTrigger(tn)),
+		})
+	}
+
+	// the columns, indexes and triggers of an added table are not diffed, so are marked as added along with the table
+	for _, r := range ho.Details {
+		for i, c := range r.Cells {
+			if c.State == "same" && ho.Cells[i].State == string(changeAdded) {
+				r.Cells[i].State = string(changeAdded)
+			}
+		}
+	}
+
+	ho.Differs = htmlDiffers(ho.Cells)
+	for _, r := range ho.Details {
+		ho.Differs = ho.Differs || htmlDiffers(r.Cells)
+	}
+
+	return ho
+}
+
+func (rb *htmlReportBuilder) routineObject(rtype, name string) *htmlObject {
+	obj := routineObjects[rtype]
+	ho := &htmlObject{
+		Object: obj,
+		Name:   name,
+		Cells:  rb.cells(htmlKey{object: obj, name: name}, describeRoutine(rtype, name)),
+	}
+	ho.Differs = htmlDiffers(ho.Cells)
+	return ho
+}
+
+func (hf *HTMLFormatter) Render(summaries connectionSummaries, sink io.Writer) error {
+	dbs := summaries.AllDatabases()
+	res := buildDiff(summaries)
+	rb := newHTMLReportBuilder(dbs, res)
+
+	report := htmlReport{
+		Title:       hf.title,
+		Baseline:    res.Baseline,
+		Databases:   make([]databaseRef, 0, len(dbs)),
+		Comparisons: make([]htmlComparison, 0, len(res.Comparisons)),
+		Objects:     make([]*htmlObject, 0),
+	}
+
+	for _, db := range dbs {
+		report.Databases = append(report.Databases, db.Ref)
+	}
+
+	for _, dd := range res.Comparisons {
+		hc := htmlComparison{Target: dd.Target}
+		hc.Added, hc.Removed, hc.Changed = dd.Counts()
+		report.Comparisons = append(report.Comparisons, hc)
+	}
+
+	for _, tn := range rb.unionOf(func(ds *databaseSummary) []string { return ds.TableNames() }) {
+		report.Objects = append(report.Objects, rb.tableObject(tn))
+	}
+
+	for _, rtype := range []string{"PROCEDURE", "FUNCTION"} {
+		for _, rn := range rb.unionOf(func(ds *databaseSummary) []string { return ds.RoutineNames(rtype) }) {
+			report.Objects = append(report.Objects, rb.routineObject(rtype, rn))
+		}
+	}
+
+	for i, ho := range report.Objects {
+		ho.ID = fmt.Sprintf("object-%d", i)
+	}
+
+	if err := htmlReportTemplate.Execute(sink, report); err != nil {
+		return fmt.Errorf("error writing output: %w", err)
+	}
+
+	return nil
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; font-size: 14px; margin: 0; display: flex; color: #1f2328; }
+nav { width: 260px; flex-shrink: 0; height: 100vh; overflow-y: auto; position: sticky; top: 0; padding: 16px; box-sizing: border-box; border-right: 1px solid #d0d7de; background: #f6f8fa; }
+nav ul { list-style: none; padding: 0; margin: 0; }
+nav li a { display: block; padding: 2px 4px; color: inherit; text-decoration: none; font-family: monospace; overflow: hidden; text-overflow: ellipsis; white-space: nowrap; }
+nav li.differs a { font-weight: bold; }
+nav li a:hover { background: #eaeef2; }
+main { flex-grow: 1; padding: 16px 24px; overflow-x: auto; }
+table { border-collapse: collapse; margin-bottom: 24px; }
+th, td { border: 1px solid #d0d7de; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; position: sticky; top: 0; }
+td.name { font-family: monospace; white-space: nowrap; }
+td.name button { border: none; background: none; cursor: pointer; width: 1.5em; padding: 0; }
+td.baseline, td.same { background: #fff; }
+.added { background: #dafbe1; }
+.removed { background: #ffebe9; }
+.changed { background: #fff8c5; }
+.absent { background: #f6f8fa; color: #8c959f; }
+tr.detail td.name { padding-left: 2.5em; }
+tr.detail td { font-size: 12px; }
+.kind { color: #656d76; font-size: 11px; text-transform: uppercase; margin-right: 4px; }
+.toolbar { margin-bottom: 16px; display: flex; gap: 16px; align-items: center; }
+.toolbar input[type=search] { padding: 4px 8px; width: 240px; }
+.legend span { display: inline-block; padding: 2px 8px; border: 1px solid #d0d7de; margin-right: 4px; }
+.hidden { display: none; }
+</style>
+</head>
+<body>
+<nav>
+<h3>Contents</h3>
+<ul>
+<li><a href="#summary">Summary</a></li>
+{{- range .Objects}}
+<li class="toc{{if .Differs}} differs{{end}}" data-for="{{.ID}}"><a href="#{{.ID}}"><span class="kind">{{.Object}}</span>{{.Name}}</a></li>
+{{- end}}
+</ul>
+</nav>
+<main>
+<h1>{{.Title}}</h1>
+<h2 id="summary">Summary</h2>
+<p>Baseline: <code>{{.Baseline}}</code></p>
+{{- if .Comparisons}}
+<table>
+<thead><tr><th>Database</th><th>Added</th><th>Removed</th><th>Changed</th></tr></thead>
+<tbody>
+{{- range .Comparisons}}
+<tr><td><code>{{.Target}}</code></td><td class="{{if .Added}}added{{end}}">{{.Added}}</td><td class="{{if .Removed}}removed{{end}}">{{.Removed}}</td><td class="{{if .Changed}}changed{{end}}">{{.Changed}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- else}}
+<p>At least two databases are required to produce a diff.</p>
+{{- end}}
+<h2>Objects</h2>
+<div class="toolbar">
+<input type="search" id="filter" placeholder="Filter by name">
+<label><input type="checkbox" id="differs-only"> Differences only</label>
+<label><input type="checkbox" id="expand-all"> Expand all</label>
+<span class="legend"><span class="added">added</span><span class="removed">removed</span><span class="changed">changed</span><span class="absent">absent</span></span>
+</div>
+<table id="objects">
+<thead><tr><th>Object</th>{{range .Databases}}<th><code>{{.}}</code></th>{{end}}</tr></thead>
+<tbody>
+{{- range .Objects}}
+{{- $id := .ID}}
+<tr id="{{.ID}}" class="object" data-name="{{.Name}}" data-differs="{{.Differs}}">
+<td class="name">{{if .Details}}<button type="button" class="toggle" aria-expanded="false">▸</button>{{else}}<button type="button" disabled></button>{{end}}<span class="kind">{{.Object}}</span>{{.Name}}</td>
+{{- range .Cells}}<td class="{{.State}}" title="{{.Title}}">{{if eq .State "removed"}}removed{{else}}{{.Value}}{{end}}</td>{{end}}
+</tr>
+{{- range .Details}}
+<tr class="detail hidden" data-parent="{{$id}}">
+<td class="name"><span class="kind">{{.Object}}</span>{{.Name}}</td>
+{{- range .Cells}}<td class="{{.State}}" title="{{.Title}}">{{if eq .State "removed"}}removed{{else}}{{.Value}}{{end}}</td>{{end}}
+</tr>
+{{- end}}
+{{- end}}
+</tbody>
+</table>
+</main>
+<script>
+(function () {
+  var filter = document.getElementById("filter");
+  var differsOnly = document.getElementById("differs-only");
+  var expandAll = document.getElementById("expand-all");
+  var objects = document.querySelectorAll("#objects tr.object");
+
+  function details(row) {
+    return document.querySelectorAll('#objects tr.detail[data-parent="' + row.id + '"]');
+  }
+
+  function setExpanded(row, expanded) {
+    var btn = row.querySelector("button.toggle");
+    if (!btn) {
+      return;
+    }
+    btn.setAttribute("aria-expanded", expanded);
+    btn.textContent = expanded ? "▾" : "▸";
+    details(row).forEach(function (d) {
+      d.classList.toggle("hidden", !expanded || row.classList.contains("hidden"));
+    });
+  }
+
+  function apply() {
+    var q = filter.value.toLowerCase();
+    objects.forEach(function (row) {
+      var visible = row.dataset.name.toLowerCase().indexOf(q) !== -1 && (!differsOnly.checked || row.dataset.differs === "true");
+      row.classList.toggle("hidden", !visible);
+      document.querySelector('nav li[data-for="' + row.id + '"]').classList.toggle("hidden", !visible);
+      var btn = row.querySelector("button.toggle");
+      setExpanded(row, btn !== null && btn.getAttribute("aria-expanded") === "true");
+    });
+  }
+
+  objects.forEach(function (row) {
+    var btn = row.querySelector("button.toggle");
+    if (btn) {
+      btn.addEventListener("click", function () {
+        setExpanded(row, btn.getAttribute("aria-expanded") !== "true");
+      });
+    }
+  });
+
+  expandAll.addEventListener("change", function () {
+    objects.forEach(function (row) { setExpanded(row, expandAll.checked); });
+  });
+  filter.addEventListener("input", apply);
+  differsOnly.addEventListener("change", apply);
+})();
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,11 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestHTMLFormatter(t *testing.T) {
+	summaries := fixtureSummaries(t)
+
+	assertGolden(t, "html.html", render(t, FormatHTML, map[string]string{"title": "Drift <report>"}, summaries))
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	FormatJSON = "json"
+)
+
+var _ Formatter = (*JSONFormatter)(nil)
+
+// JSONFormatter renders the structured diff between the baseline database and every other database as JSON
+type JSONFormatter struct {
+	pretty bool
+}
+
+func newJSONFormatter(_ *cli.Context, cfg map[string]string) (Formatter, error) {
+	var err error
+
+	jf := JSONFormatter{}
+
+	if v, ok := cfg["pretty"]; ok {
+		if jf.pretty, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("error parsing flag \"pretty\" value %q as bool: %w", v, err)
+		}
+	}
+
+	return &jf, nil
+}
+
+func (*JSONFormatter) Type() string {
+	return FormatJSON
+}
+
+type jsonDifference struct {
+	Kind       changeKind `json:"kind"`
+	Object     objectKind `json:"object"`
+	Path       string     `json:"path"`
+	Connection string     `json:"connection"`
+	Database   string     `json:"database"`
+	Table      string     `json:"table,omitempty"`
+	Column     string     `json:"column,omitempty"`
+	Name       string     `json:"name"`
+	Property   string     `json:"property,omitempty"`
+	Values     jsonValues `json:"values"`
+}
+
+// jsonValues holds the value of a difference in the baseline and target databases, which is null for the database an
+// added or removed object is missing from
+type jsonValues struct {
+	Baseline *string `json:"baseline"`
+	Target   *string `json:"target"`
+}
+
+type jsonComparison struct {
+	Baseline    databaseRef      `json:"baseline"`
+	Target      databaseRef      `json:"target"`
+	Objects     int              `json:"objects"`
+	Differences []jsonDifference `json:"differences"`
+}
+
+type jsonDiff struct {
+	Baseline    databaseRef      `json:"baseline"`
+	Databases   []databaseRef    `json:"databases"`
+	Comparisons []jsonComparison `json:"comparisons"`
+}
+
+// jsonPath returns the slash-separated path of a differing object, e.g. "connection/database/table/column"
+func jsonPath(ref databaseRef, od objectDiff) string {
+	parts := []string{ref.Connection, ref.Database}
+	if od.Parent != "" {
+		parts = append(parts, od.Parent)
+	}
+	return strings.Join(append(parts, od.Name), "/")
+}
+
+// newJSONDifference converts a difference into its JSON representation
+func newJSONDifference(dd *databaseDiff, od objectDiff) jsonDifference {
+	jd := jsonDifference{
+		Kind:       od.Kind,
+		Object:     od.Object,
+		Path:       jsonPath(dd.Target, od),
+		Connection: dd.Target.Connection,
+		Database:   dd.Target.Database,
+		Table:      od.TableName(),
+		Column:     od.ColumnName(),
+		Name:       od.Name,
+		Property:   od.Property,
+	}
+
+	base, target := od.Baseline, od.Target
+
+	switch od.Kind {
+	case changeAdded:
+		jd.Values.Target = &target
+	case changeRemoved:
+		jd.Values.Baseline = &base
+	default:
+		jd.Values.Baseline, jd.Values.Target = &base, &target
+	}
+
+	return jd
+}
+
+func (jf *JSONFormatter) Render(summaries connectionSummaries, sink io.Writer) error {
+	res := buildDiff(summaries)
+
+	out := jsonDiff{
+		Baseline:    res.Baseline,
+		Databases:   make([]databaseRef, 0),
+		Comparisons: make([]jsonComparison, 0, len(res.Comparisons)),
+	}
+
+	for _, db := range summaries.AllDatabases() {
+		out.Databases = append(out.Databases, db.Ref)
+	}
+
+	for _, dd := range res.Comparisons {
+		jc := jsonComparison{
+			Baseline:    dd.Baseline,
+			Target:      dd.Target,
+			Objects:     dd.ObjectCount(),
+			Differences: make([]jsonDifference, 0, len(dd.Differences)),
+		}
+		for _, od := range dd.Differences {
+			jc.Differences = append(jc.Differences, newJSONDifference(dd, od))
+		}
+		out.Comparisons = append(out.Comparisons, jc)
+	}
+
+	var (
+		b   []byte
+		err error
+	)
+	if jf.pretty {
+		b, err = json.MarshalIndent(out, "", "  ")
+	} else {
+		b, err = json.Marshal(out)
+	}
+	if err != nil {
+		return fmt.Errorf("error json-marshalling diff: %w", err)
+	}
+
+	if _, err = sink.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("error writing output: %w", err)
+	}
+
+	return nil
+}
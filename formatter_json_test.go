@@ -0,0 +1,31 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJSONFormatter(t *testing.T) {
+	summaries := fixtureSummaries(t)
+
+	assertGolden(t, "json.json", render(t, FormatJSON, map[string]string{"pretty": "true"}, summaries))
+}
+
+func TestJSONFormatterEqualRefs(t *testing.T) {
+	// both databases are named identically, so values must not be keyed by database
+	summaries := connectionSummaries{ddlSummary(t, "db", "base.sql"), ddlSummary(t, "db", "target.sql")}
+
+	var out jsonDiff
+	if err := json.Unmarshal(render(t, FormatJSON, nil, summaries), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, jd := range out.Comparisons[0].Differences {
+		if jd.Kind != changeChanged {
+			continue
+		}
+		if jd.Values.Baseline == nil || jd.Values.Target == nil || *jd.Values.Baseline == *jd.Values.Target {
+			t.Errorf("expected distinct baseline and target values for %s, got %+v", jd.Path, jd.Values)
+		}
+	}
+}
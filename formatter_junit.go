@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	FormatJUnit = "junit"
+)
+
+const (
+	junitPerTable    = "table"
+	junitPerDatabase = "database"
+)
+
+var _ Formatter = (*JUnitFormatter)(nil)
+
+// JUnitFormatter renders the diff as a JUnit XML report.  Each compared database is a test suite, containing either
+// one test case per table and routine or a single test case for the whole database, which fails if drift is found.
+type JUnitFormatter struct {
+	name string
+	per  string
+}
+
+func newJUnitFormatter(_ *cli.Context, cfg map[string]string) (Formatter, error) {
+	jf := JUnitFormatter{
+		name: "mysql-diff",
+		per:  junitPerTable,
+	}
+
+	if v, ok := cfg["name"]; ok {
+		jf.name = v
+	}
+
+	if v, ok := cfg["per"]; ok {
+		switch v {
+		case junitPerTable, junitPerDatabase:
+			jf.per = v
+		default:
+			return nil, fmt.Errorf("unknown \"per\" value %q specified, expected one of %v", v, []string{junitPerDatabase, junitPerTable})
+		}
+	}
+
+	return &jf, nil
+}
+
+func (*JUnitFormatter) Type() string {
+	return FormatJUnit
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestSuites struct {
+	XMLName    xml.Name         `xml:"testsuites"`
+	Name       string           `xml:"name,attr"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	TestSuites []junitTestSuite `xml:"testsuite"`
+}
+
+// newJUnitFailure returns a failure listing the provided differences, or nil if there are none
+func newJUnitFailure(diffs []objectDiff) *junitFailure {
+	if len(diffs) == 0 {
+		return nil
+	}
+
+	lines := make([]string, len(diffs))
+	for i, od := range diffs {
+		lines[i] = od.String()
+	}
+
+	msg := "1 difference found"
+	if len(diffs) != 1 {
+		msg = fmt.Sprintf("%d differences found", len(diffs))
+	}
+
+	return &junitFailure{
+		Message: msg,
+		Type:    "SchemaDrift",
+		Text:    strings.Join(lines, "\n"),
+	}
+}
+
+// testCases returns one test case per table and routine present in either database
+func (jf *JUnitFormatter) testCases(dd *databaseDiff, baseline, target *databaseSummary) []junitTestCase {
+	type caseKey struct {
+		object objectKind
+		name   string
+	}
+
+	failures := make(map[caseKey][]objectDiff)
+	for _, g := range dd.Groups() {
+		failures[caseKey{g.Object, g.Name}] = g.Differences
+	}
+
+	classname := fmt.Sprintf("%s.%s", dd.Target.Connection, dd.Target.Database)
+
+	out := make([]junitTestCase, 0)
+
+	for _, tn := range unionNames(baseline.TableNames(), target.TableNames()) {
+		out = append(out, junitTestCase{
+			Name:      fmt.Sprintf("%s %s", objectTable, tn),
+			ClassName: classname,
+			Failure:   newJUnitFailure(failures[caseKey{objectTable, tn}]),
+		})
+	}
+
+	for _, rtype := range []string{"PROCEDURE", "FUNCTION"} {
+		obj := routineObjects[rtype]
+		for _, rn := range unionNames(baseline.RoutineNames(rtype), target.RoutineNames(rtype)) {
+			out = append(out, junitTestCase{
+				Name:      fmt.Sprintf("%s %s", obj, rn),
+				ClassName: classname,
+				Failure:   newJUnitFailure(failures[caseKey{obj, rn}]),
+			})
+		}
+	}
+
+	return out
+}
+
+func (jf *JUnitFormatter) Render(summaries connectionSummaries, sink io.Writer) error {
+	dbs := summaries.AllDatabases()
+	res := buildDiff(summaries)
+
+	out := junitTestSuites{
+		Name:       jf.name,
+		TestSuites: make([]junitTestSuite, 0, len(res.Comparisons)),
+	}
+
+	for i, dd := range res.Comparisons {
+		ts := junitTestSuite{
+			Name: fmt.Sprintf("%s vs %s", dd.Baseline, dd.Target),
+		}
+
+		if jf.per == junitPerDatabase {
+			ts.TestCases = []junitTestCase{{
+				Name:      "schema drift",
+				ClassName: fmt.Sprintf("%s.%s", dd.Target.Connection, dd.Target.Database),
+				Failure:   newJUnitFailure(dd.Differences),
+			}}
+		} else {
+			ts.TestCases = jf.testCases(dd, dbs[0].Summary, dbs[i+1].Summary)
+		}
+
+		for _, tc := range ts.TestCases {
+			ts.Tests++
+			if tc.Failure != nil {
+				ts.Failures++
+			}
+		}
+
+		out.Tests += ts.Tests
+		out.Failures += ts.Failures
+		out.TestSuites = append(out.TestSuites, ts)
+	}
+
+	b, err := xml.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error xml-marshalling diff: %w", err)
+	}
+
+	if _, err = sink.Write([]byte(xml.Header + string(b) + "\n")); err != nil {
+		return fmt.Errorf("error writing output: %w", err)
+	}
+
+	return nil
+}
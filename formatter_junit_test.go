@@ -0,0 +1,28 @@
+package main
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func TestJUnitFormatter(t *testing.T) {
+	summaries := fixtureSummaries(t)
+
+	tests := []struct {
+		golden string
+		cfg    map[string]string
+	}{
+		{"junit.xml", nil},
+		{"junit-database.xml", map[string]string{"name": "drift", "per": "database"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			out := render(t, FormatJUnit, tt.cfg, summaries)
+			if err := xml.Unmarshal(out, new(struct{})); err != nil {
+				t.Errorf("expected well-formed XML: %v", err)
+			}
+			assertGolden(t, tt.golden, out)
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	FormatMarkdown = "markdown"
+)
+
+var _ Formatter = (*MarkdownFormatter)(nil)
+
+// MarkdownFormatter renders the diff as GitHub-flavored Markdown, suitable for posting as a pull request comment
+type MarkdownFormatter struct {
+	title    string
+	collapse bool
+	maxRows  int
+}
+
+func newMarkdownFormatter(_ *cli.Context, cfg map[string]string) (Formatter, error) {
+	var err error
+
+	mf := MarkdownFormatter{
+		title:    "Schema Diff",
+		collapse: true,
+	}
+
+	if v, ok := cfg["title"]; ok {
+		mf.title = v
+	}
+
+	if v, ok := cfg["collapse"]; ok {
+		if mf.collapse, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("error parsing flag \"collapse\" value %q as bool: %w", v, err)
+		}
+	}
+
+	if v, ok := cfg["max-rows"]; ok {
+		if mf.maxRows, err = strconv.Atoi(v); err != nil {
+			return nil, fmt.Errorf("error parsing flag \"max-rows\" value %q as int: %w", v, err)
+		}
+		if mf.maxRows < 0 {
+			return nil, fmt.Errorf("flag \"max-rows\" must not be negative, saw %d", mf.maxRows)
+		}
+	}
+
+	return &mf, nil
+}
+
+func (*MarkdownFormatter) Type() string {
+	return FormatMarkdown
+}
+
+// markdownCell escapes a value for use within a Markdown table cell
+func markdownCell(v string) string {
+	v = strings.ReplaceAll(v, "|", `\|`)
+	v = strings.ReplaceAll(v, "\r\n", "<br>")
+	return strings.ReplaceAll(v, "\n", "<br>")
+}
+
+// markdownCode wraps a value in a code span, escaped for use within a Markdown table cell
+func markdownCode(v string) string {
+	if v == "" {
+		return ""
+	}
+	fence := "`"
+	for strings.Contains(v, fence) {
+		fence += "`"
+	}
+	// values beginning or ending with a backtick must be padded to be distinguished from the fence
+	if len(fence) > 1 {
+		v = " " + v + " "
+	}
+	return markdownCell(fence + v + fence)
+}
+
+func (mf *MarkdownFormatter) Render(summaries connectionSummaries, sink io.Writer) error {
+	res := buildDiff(summaries)
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## %s\n\n", mf.title)
+
+	if len(res.Comparisons) == 0 {
+		b.WriteString("At least two databases are required to produce a diff.\n")
+		return mf.write(sink, b.String())
+	}
+
+	fmt.Fprintf(&b, "Baseline: %s\n\n", markdownCode(res.Baseline.String()))
+
+	b.WriteString("| Database | Added | Removed | Changed |\n")
+	b.WriteString("|----------|------:|--------:|--------:|\n")
+	for _, dd := range res.Comparisons {
+		added, removed, changed := dd.Counts()
+		fmt.Fprintf(&b, "| %s | %d | %d | %d |\n", markdownCode(dd.Target.String()), added, removed, changed)
+	}
+	b.WriteString("\n")
+
+	open := " open"
+	if mf.collapse {
+		open = ""
+	}
+
+	for _, dd := range res.Comparisons {
+		fmt.Fprintf(&b, "### %s\n\n", markdownCode(dd.Target.String()))
+
+		groups := dd.Groups()
+		if len(groups) == 0 {
+			b.WriteString("No differences.\n\n")
+			continue
+		}
+
+		for _, g := range groups {
+			fmt.Fprintf(
+				&b,
+				"<details%s>\n<summary>%s <code>%s</code> (%d)</summary>\n\n",
+				open,
+				g.Object,
+				html.EscapeString(g.Name),
+				len(g.Differences),
+			)
+
+			fmt.Fprintf(&b, "| Object | Change | Property | %s | %s |\n", markdownCode(dd.Baseline.String()), markdownCode(dd.Target.String()))
+			b.WriteString("|--------|--------|----------|---|---|\n")
+
+			rows := g.Differences
+			if mf.maxRows > 0 && len(rows) > mf.maxRows {
+				rows = rows[:mf.maxRows]
+			}
+
+			for _, od := range rows {
+				fmt.Fprintf(
+					&b,
+					"| %s %s | %s | %s | %s | %s |\n",
+					od.Object,
+					markdownCode(od.Name),
+					od.Kind,
+					od.Property,
+					markdownCode(od.Baseline),
+					markdownCode(od.Target),
+				)
+			}
+
+			if n := len(g.Differences) - len(rows); n > 0 {
+				fmt.Fprintf(&b, "\n_%d more differences not shown._\n", n)
+			}
+
+			b.WriteString("\n</details>\n\n")
+		}
+	}
+
+	return mf.write(sink, b.String())
+}
+
+func (*MarkdownFormatter) write(sink io.Writer, out string) error {
+	if _, err := sink.Write([]byte(out)); err != nil {
+		return fmt.Errorf("error writing output: %w", err)
+	}
+	return nil
+}
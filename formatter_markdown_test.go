@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestMarkdownFormatter(t *testing.T) {
+	summaries := fixtureSummaries(t)
+
+	tests := []struct {
+		golden string
+		cfg    map[string]string
+	}{
+		{"markdown.md", nil},
+		{"markdown-expanded.md", map[string]string{"title": "Drift", "collapse": "false", "max-rows": "2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			assertGolden(t, tt.golden, render(t, FormatMarkdown, tt.cfg, summaries))
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	FormatMatrix = "matrix"
+)
+
+var _ Formatter = (*MatrixFormatter)(nil)
+
+// MatrixFormatter renders the number of differing objects between every pair of databases, followed by the list of
+// clusters of databases sharing an identical fingerprint.
+type MatrixFormatter struct {
+	style table.Style
+
+	matrix bool
+}
+
+func newMatrixFormatter(_ *cli.Context, cfg map[string]string) (Formatter, error) {
+	var err error
+
+	mf := MatrixFormatter{
+		style:  table.StyleDefault,
+		matrix: true,
+	}
+
+	if v, ok := cfg["matrix"]; ok {
+		if mf.matrix, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("error parsing flag \"matrix\" value %q as bool: %w", v, err)
+		}
+	}
+
+	if v, ok := cfg["style"]; ok {
+		if mf.style, ok = styleMap[v]; !ok {
+			return nil, fmt.Errorf("unknown style %q specified, expected one of %v", v, styleKeys())
+		}
+	}
+
+	return &mf, nil
+}
+
+func (*MatrixFormatter) Type() string {
+	return FormatMatrix
+}
+
+type databaseCluster struct {
+	Fingerprint string
+	Members     []summaryDatabase
+}
+
+// clusterDatabases groups databases by fingerprint, preserving the order in which each fingerprint was first seen.  It
+// also returns the index of the cluster of each database, by position within dbs.
+func clusterDatabases(dbs []summaryDatabase) ([]*databaseCluster, []int) {
+	out := make([]*databaseCluster, 0)
+	clusterOf := make([]int, len(dbs))
+	idx := make(map[string]int)
+
+	for i, db := range dbs {
+		fp := fingerprintDatabase(db.Summary)
+		if ci, ok := idx[fp]; ok {
+			out[ci].Members = append(out[ci].Members, db)
+			clusterOf[i] = ci
+			continue
+		}
+		idx[fp] = len(out)
+		clusterOf[i] = len(out)
+		out = append(out, &databaseCluster{Fingerprint: fp, Members: []summaryDatabase{db}})
+	}
+
+	return out, clusterOf
+}
+
+func (mf *MatrixFormatter) Render(summaries connectionSummaries, sink io.Writer) error {
+	dbs := summaries.AllDatabases()
+	clusters, clusterOf := clusterDatabases(dbs)
+
+	var b strings.Builder
+
+	if mf.matrix {
+		// databases within a cluster are identical, so only compare one member of each cluster.
+		counts := make([][]int, len(clusters))
+		for i := range clusters {
+			counts[i] = make([]int, len(clusters))
+			for j := range i {
+				n := countObjects(compareDatabases(clusters[i].Members[0].Summary, clusters[j].Members[0].Summary))
+				counts[i][j], counts[j][i] = n, n
+			}
+		}
+
+		tw := table.NewWriter()
+		tw.SetStyle(mf.style)
+
+		hdr := table.Row{""}
+		for _, db := range dbs {
+			hdr = append(hdr, db.Ref.String())
+		}
+		tw.AppendHeader(hdr)
+
+		for i, a := range dbs {
+			row := table.Row{a.Ref.String()}
+			for j := range dbs {
+				if i == j {
+					row = append(row, "-")
+				} else {
+					row = append(row, counts[clusterOf[i]][clusterOf[j]])
+				}
+			}
+			tw.AppendRow(row)
+		}
+
+		b.WriteString(tw.Render())
+		b.WriteString("\n\n")
+	}
+
+	tw := table.NewWriter()
+	tw.SetStyle(mf.style)
+	tw.AppendHeader(table.Row{"Cluster", "Fingerprint", "Databases"})
+
+	for i, c := range clusters {
+		names := make([]string, len(c.Members))
+		for j, m := range c.Members {
+			names[j] = m.Ref.String()
+		}
+		tw.AppendRow(table.Row{i + 1, c.Fingerprint[:12], strings.Join(names, "\n")})
+	}
+
+	b.WriteString(tw.Render())
+	b.WriteString("\n")
+
+	if _, err := sink.Write([]byte(b.String())); err != nil {
+		return fmt.Errorf("error writing output: %w", err)
+	}
+
+	return nil
+}
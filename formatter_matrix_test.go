@@ -0,0 +1,36 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatrixFormatter(t *testing.T) {
+	summaries := fixtureSummaries(t)
+	summaries = append(summaries, ddlSummary(t, "copy", "base.sql"))
+
+	assertGolden(t, "matrix.txt", render(t, FormatMatrix, nil, summaries))
+}
+
+func TestMatrixFormatterDuplicateRefs(t *testing.T) {
+	// both sources share a label and database name, but differ in schema
+	summaries := connectionSummaries{ddlSummary(t, "db", "base.sql"), ddlSummary(t, "db", "target.sql")}
+
+	out := string(render(t, FormatMatrix, map[string]string{"style": "light"}, summaries))
+
+	var rows []string
+	for _, l := range strings.Split(out, "\n") {
+		if strings.HasPrefix(l, "│ `db`.`shop`") {
+			rows = append(rows, l)
+		}
+	}
+
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 matrix rows, got %d:\n%s", len(rows), out)
+	}
+	for _, r := range rows {
+		if strings.Count(r, " - ") != 1 || !strings.Contains(r, " 6 ") {
+			t.Errorf("expected row %q to compare the database with itself once, and with the other database", r)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	FormatMermaid = "mermaid"
+)
+
+var _ Formatter = (*MermaidFormatter)(nil)
+
+// MermaidFormatter renders the schema as a Mermaid entity-relationship diagram.  When databases are compared, one
+// diagram is rendered per comparison with differing entities styled by class and differing attributes and
+// relationships annotated, as Mermaid cannot style them individually.
+type MermaidFormatter struct {
+	fence bool
+}
+
+func newMermaidFormatter(_ *cli.Context, cfg map[string]string) (Formatter, error) {
+	var err error
+
+	mf := MermaidFormatter{}
+
+	if v, ok := cfg["fence"]; ok {
+		if mf.fence, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("error parsing flag \"fence\" value %q as bool: %w", v, err)
+		}
+	}
+
+	return &mf, nil
+}
+
+func (*MermaidFormatter) Type() string {
+	return FormatMermaid
+}
+
+var (
+	// mermaidInvalidName matches the characters Mermaid does not accept within entity and attribute names
+	mermaidInvalidName = regexp.MustCompile(`[^A-Za-z0-9_\-]`)
+	// mermaidInvalidType matches the characters Mermaid does not accept within attribute types
+	mermaidInvalidType = regexp.MustCompile(`[^A-Za-z0-9_\-()\[\]]`)
+)
+
+// mermaidName replaces every character Mermaid does not accept within a name with an underscore
+func mermaidName(v string) string {
+	return mermaidInvalidName.ReplaceAllString(v, "_")
+}
+
+// mermaidType reduces a column type to one Mermaid accepts.  The values of enum and set types are dropped.
+func mermaidType(v string) string {
+	if i := strings.IndexByte(v, '('); i != -1 {
+		switch strings.ToLower(v[:i]) {
+		case "enum", "set":
+			v = v[:i]
+		}
+	}
+	return mermaidInvalidType.ReplaceAllString(v, "_")
+}
+
+func (mf *MermaidFormatter) Render(summaries connectionSummaries, sink io.Writer) error {
+	var b strings.Builder
+
+	for i, d := range buildERDiagrams(summaries) {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		if mf.fence {
+			b.WriteString("```mermaid\n")
+		}
+
+		fmt.Fprintf(&b, "---\ntitle: %s\n---\nerDiagram\n", strconv.Quote(d.Title))
+
+		for _, et := range d.Tables {
+			name := mermaidName(et.Name)
+			if et.State != "" {
+				name += ":::" + string(et.State)
+			}
+
+			fmt.Fprintf(&b, "    %s {\n", name)
+			for _, ec := range et.Columns {
+				line := fmt.Sprintf("%s %s", mermaidType(ec.Type), mermaidName(ec.Name))
+				if len(ec.Keys) > 0 {
+					line += " " + strings.Join(ec.Keys, ", ")
+				}
+				if ec.State != "" {
+					line += fmt.Sprintf(" %q", ec.State)
+				}
+				fmt.Fprintf(&b, "        %s\n", line)
+			}
+			b.WriteString("    }\n")
+		}
+
+		for _, ee := range d.Edges {
+			rel := "}o--||"
+			if ee.Optional {
+				rel = "}o--o|"
+			}
+
+			label := ee.Name
+			if ee.State != "" {
+				label += fmt.Sprintf(" (%s)", ee.State)
+			}
+
+			fmt.Fprintf(&b, "    %s %s %s : %q\n", mermaidName(ee.Table), rel, mermaidName(ee.RefTable), label)
+		}
+
+		if d.Diff {
+			for _, state := range []changeKind{changeAdded, changeRemoved, changeChanged} {
+				fmt.Fprintf(&b, "    classDef %s stroke:%s,stroke-width:2px\n", state, erdColors[state])
+			}
+		}
+
+		if mf.fence {
+			b.WriteString("```\n")
+		}
+	}
+
+	if _, err := sink.Write([]byte(b.String())); err != nil {
+		return fmt.Errorf("error writing output: %w", err)
+	}
+
+	return nil
+}
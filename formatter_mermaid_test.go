@@ -0,0 +1,24 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestMermaidFormatter(t *testing.T) {
+	summaries := fixtureSummaries(t)
+
+	tests := []struct {
+		golden    string
+		cfg       map[string]string
+		summaries connectionSummaries
+	}{
+		{"mermaid.mmd", nil, summaries},
+		{"mermaid-single.md", map[string]string{"fence": "true"}, summaries[:1]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			assertGolden(t, tt.golden, render(t, FormatMermaid, tt.cfg, tt.summaries))
+		})
+	}
+}
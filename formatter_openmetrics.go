@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	FormatOpenMetrics = "openmetrics"
+)
+
+var _ Formatter = (*OpenMetricsFormatter)(nil)
+
+// metricNamePattern matches valid metric name prefixes
+var metricNamePattern = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+// OpenMetricsFormatter renders gauges counting the drifted objects of each database compared against the baseline, in
+// the OpenMetrics text format read by Prometheus and the node_exporter textfile collector.
+type OpenMetricsFormatter struct {
+	prefix string
+}
+
+func newOpenMetricsFormatter(_ *cli.Context, cfg map[string]string) (Formatter, error) {
+	of := OpenMetricsFormatter{
+		prefix: "mysql_diff",
+	}
+
+	if v, ok := cfg["prefix"]; ok {
+		if !metricNamePattern.MatchString(v) {
+			return nil, fmt.Errorf("flag \"prefix\" value %q is not a valid metric name", v)
+		}
+		of.prefix = v
+	}
+
+	return &of, nil
+}
+
+func (*OpenMetricsFormatter) Type() string {
+	return FormatOpenMetrics
+}
+
+// metricObjects maps each object kind to the plural used in metric names, in the order the metrics are written
+var metricObjects = []struct {
+	Object objectKind
+	Plural string
+}{
+	{objectTable, "tables"},
+	{objectColumn, "columns"},
+	{objectIndex, "indexes"},
+	{objectForeignKey, "foreign_keys"},
+	{objectProcedure, "procedures"},
+	{objectFunction, "functions"},
+	{objectTrigger, "triggers"},
+}
+
+// metricStates maps each change kind to the state used in metric names and help text
+var metricStates = []struct {
+	Kind  changeKind
+	State string
+	Help  string
+}{
+	{changeRemoved, "missing", "in the baseline but missing from the database"},
+	{changeAdded, "unexpected", "in the database but not in the baseline"},
+	{changeChanged, "changed", "which differ from the baseline"},
+}
+
+// metricLabel escapes a label value
+func metricLabel(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+// countDrift returns the number of distinct objects of the provided kind with a difference of the provided kind
+func countDrift(diffs []objectDiff, object objectKind, kind changeKind) int {
+	return countObjects(filterDiffs(diffs, func(od objectDiff) bool { return od.Object == object && od.Kind == kind }))
+}
+
+func (of *OpenMetricsFormatter) Render(summaries connectionSummaries, sink io.Writer) error {
+	res := buildDiff(summaries)
+
+	var b strings.Builder
+
+	gauge := func(name, help string, value func(dd *databaseDiff) int) {
+		name = of.prefix + "_" + name
+		fmt.Fprintf(&b, "# TYPE %s gauge\n", name)
+		fmt.Fprintf(&b, "# HELP %s %s\n", name, help)
+		for _, dd := range res.Comparisons {
+			fmt.Fprintf(
+				&b,
+				"%s{source=\"%s\",database=\"%s\",baseline_source=\"%s\",baseline_database=\"%s\"} %d\n",
+				name,
+				metricLabel(dd.Target.Connection),
+				metricLabel(dd.Target.Database),
+				metricLabel(dd.Baseline.Connection),
+				metricLabel(dd.Baseline.Database),
+				value(dd),
+			)
+		}
+	}
+
+	for _, mo := range metricObjects {
+		for _, ms := range metricStates {
+			gauge(
+				mo.Plural+"_"+ms.State,
+				fmt.Sprintf("Number of %s %s.", strings.ReplaceAll(mo.Plural, "_", " "), ms.Help),
+				func(dd *databaseDiff) int { return countDrift(dd.Differences, mo.Object, ms.Kind) },
+			)
+		}
+	}
+
+	gauge("objects_drifted", "Number of objects with at least one difference from the baseline.", func(dd *databaseDiff) int {
+		return dd.ObjectCount()
+	})
+
+	name := of.prefix + "_last_run_timestamp"
+	fmt.Fprintf(&b, "# TYPE %s gauge\n", name)
+	fmt.Fprintf(&b, "# HELP %s Time the comparison was run, in seconds since the epoch.\n", name)
+	fmt.Fprintf(&b, "%s %.3f\n", name, float64(time.Now().UnixMilli())/1000)
+
+	b.WriteString("# EOF\n")
+
+	if _, err := sink.Write([]byte(b.String())); err != nil {
+		return fmt.Errorf("error writing output: %w", err)
+	}
+
+	return nil
+}
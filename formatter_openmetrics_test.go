@@ -0,0 +1,60 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// metricTimestamp matches the value of the last run timestamp, which changes with every run
+var metricTimestamp = regexp.MustCompile(`(?m)^(\w+_last_run_timestamp) \d+\.\d{3}$`)
+
+func TestOpenMetricsFormatterGolden(t *testing.T) {
+	got := render(t, FormatOpenMetrics, nil, fixtureSummaries(t))
+	if !metricTimestamp.Match(got) {
+		t.Fatalf("expected a last run timestamp, got:\n%s", got)
+	}
+
+	assertGolden(t, "openmetrics.txt", metricTimestamp.ReplaceAll(got, []byte("$1 0.000")))
+}
+
+func TestOpenMetricsFormatterPrefix(t *testing.T) {
+	got := string(render(t, FormatOpenMetrics, map[string]string{"prefix": "schema:drift"}, fixtureSummaries(t)))
+
+	for _, line := range strings.Split(strings.TrimSuffix(got, "\n"), "\n") {
+		if line == "# EOF" {
+			continue
+		}
+		name := strings.TrimPrefix(strings.TrimPrefix(line, "# TYPE "), "# HELP ")
+		if !strings.HasPrefix(name, "schema:drift_") {
+			t.Errorf("expected line to use the configured prefix: %q", line)
+		}
+	}
+
+	for _, prefix := range []string{"", "9lives", "mysql-diff", "a b"} {
+		if _, err := newOpenMetricsFormatter(nil, map[string]string{"prefix": prefix}); err == nil {
+			t.Errorf("expected prefix %q to be rejected", prefix)
+		}
+	}
+}
+
+func TestOpenMetricsFormatterLabels(t *testing.T) {
+	summaries := fixtureSummaries(t)
+	summaries[1].Label = "say \"hi\"\\\n"
+
+	got := string(render(t, FormatOpenMetrics, nil, summaries))
+	if want := `source="say \"hi\"\\\n"`; !strings.Contains(got, want) {
+		t.Errorf("expected escaped label %s, got:\n%s", want, got)
+	}
+}
+
+func TestOpenMetricsFormatterNoComparisons(t *testing.T) {
+	got := string(render(t, FormatOpenMetrics, nil, fixtureSummaries(t)[:1]))
+
+	if strings.Contains(got, "{") {
+		t.Errorf("expected no series without comparisons, got:\n%s", got)
+	}
+	if !strings.HasSuffix(got, "# EOF\n") {
+		t.Errorf("expected output to end with # EOF, got:\n%s", got)
+	}
+}
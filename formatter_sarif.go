@@ -0,0 +1,335 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	FormatSARIF = "sarif"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+var _ Formatter = (*SARIFFormatter)(nil)
+
+// SARIFFormatter renders every difference between the baseline and the other databases as a SARIF 2.1.0 result, for
+// upload to code scanning tools.
+type SARIFFormatter struct {
+	pretty bool
+}
+
+func newSARIFFormatter(_ *cli.Context, cfg map[string]string) (Formatter, error) {
+	var err error
+
+	sf := SARIFFormatter{}
+
+	if v, ok := cfg["pretty"]; ok {
+		if sf.pretty, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("error parsing flag \"pretty\" value %q as bool: %w", v, err)
+		}
+	}
+
+	return &sf, nil
+}
+
+func (*SARIFFormatter) Type() string {
+	return FormatSARIF
+}
+
+// sarifRule describes a class of difference.  Level is one of the SARIF levels "error", "warning" or "note".
+type sarifRule struct {
+	ID          string
+	Description string
+	Level       string
+}
+
+// sarifRules lists every rule a difference may be reported under, in the order they are listed in the report
+var sarifRules = []sarifRule{
+	{"missing-table", "A table or view in the baseline is missing", "error"},
+	{"unexpected-table", "A table or view is not in the baseline", "warning"},
+	{"table-type-mismatch", "A table is a view in one database and a base table in the other", "error"},
+	{"view-definition-mismatch", "A view is defined by a different query", "warning"},
+	{"missing-column", "A column in the baseline is missing", "error"},
+	{"unexpected-column", "A column is not in the baseline", "warning"},
+	{"type-mismatch", "A column has a different data type", "error"},
+	{"nullability-mismatch", "A column differs in whether it accepts NULL", "warning"},
+	{"default-mismatch", "A column has a different default value", "warning"},
+	{"column-attribute-mismatch", "A column differs in its key or extra attributes", "note"},
+	{"missing-index", "An index in the baseline is missing", "warning"},
+	{"unexpected-index", "An index is not in the baseline", "note"},
+	{"index-mismatch", "An index differs in uniqueness or columns", "warning"},
+	{"missing-foreign-key", "A foreign key in the baseline is missing", "error"},
+	{"unexpected-foreign-key", "A foreign key is not in the baseline", "warning"},
+	{"foreign-key-mismatch", "A foreign key differs in its columns or referenced table", "error"},
+	{"missing-routine", "A stored procedure or function in the baseline is missing", "error"},
+	{"unexpected-routine", "A stored procedure or function is not in the baseline", "warning"},
+	{"routine-mismatch", "A stored procedure or function is defined differently", "warning"},
+	{"missing-trigger", "A trigger in the baseline is missing", "error"},
+	{"unexpected-trigger", "A trigger is not in the baseline", "warning"},
+	{"trigger-mismatch", "A trigger is defined differently", "warning"},
+}
+
+// sarifRuleID returns the ID of the rule a difference is reported under
+func sarifRuleID(od objectDiff) string {
+	noun := string(od.Object)
+	switch od.Object {
+	case objectProcedure, objectFunction:
+		noun = "routine"
+	case objectForeignKey:
+		noun = "foreign-key"
+	case objectTable:
+		if od.Kind == changeChanged && od.Property == "type" {
+			return "table-type-mismatch"
+		} else if od.Kind == changeChanged {
+			return "view-definition-mismatch"
+		}
+	case objectColumn:
+		if od.Kind == changeChanged {
+			switch od.Property {
+			case "type":
+				return "type-mismatch"
+			case "nullable":
+				return "nullability-mismatch"
+			case "default":
+				return "default-mismatch"
+			default:
+				return "column-attribute-mismatch"
+			}
+		}
+	}
+
+	switch od.Kind {
+	case changeAdded:
+		return "unexpected-" + noun
+	case changeRemoved:
+		return "missing-" + noun
+	default:
+		return noun + "-mismatch"
+	}
+}
+
+// objectOrigin returns the location the differing object, or its table if the object itself does not exist, is
+// defined at within the provided database.  Only summaries built from SQL files contain locations.
+func objectOrigin(ds *databaseSummary, od objectDiff) *sourceLocation {
+	switch od.Object {
+	case objectTable, objectColumn, objectIndex, objectForeignKey:
+		ts, ok := ds.FindTable(od.TableName())
+		if !ok {
+			return nil
+		}
+		if cs, ok := ts.FindColumn(od.Name); ok && od.Object == objectColumn && cs.Origin != nil {
+			return cs.Origin
+		}
+		if is, ok := ts.FindIndex(od.Name); ok && od.Object == objectIndex && is.Origin != nil {
+			return is.Origin
+		}
+		if fk, ok := ts.FindForeignKey(od.Name); ok && od.Object == objectForeignKey && fk.Origin != nil {
+			return fk.Origin
+		}
+		return ts.Origin
+	case objectProcedure, objectFunction:
+		rs, _ := ds.FindRoutine(strings.ToUpper(string(od.Object)), od.Name)
+		return rs.Origin
+	case objectTrigger:
+		ts, _ := ds.FindTrigger(od.Name)
+		return ts.Origin
+	default:
+		return nil
+	}
+}
+
+// sarifURI returns the artifact URI of a source file, which is relative unless the file was specified by an absolute
+// path.
+func sarifURI(file string) string {
+	if filepath.IsAbs(file) {
+		return (&url.URL{Scheme: "file", Path: filepath.ToSlash(file)}).String()
+	}
+	return (&url.URL{Path: filepath.ToSlash(file)}).String()
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifReportingConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifReportingDescriptor struct {
+	ID                   string                      `json:"id"`
+	ShortDescription     sarifMessage                `json:"shortDescription"`
+	DefaultConfiguration sarifReportingConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifDriver struct {
+	Name           string                     `json:"name"`
+	Version        string                     `json:"version"`
+	InformationURI string                     `json:"informationUri"`
+	Rules          []sarifReportingDescriptor `json:"rules"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+	Properties          map[string]string `json:"properties"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+func newSARIFResult(dd *databaseDiff, od objectDiff, baseline, target *databaseSummary) sarifResult {
+	ruleID := sarifRuleID(od)
+
+	ruleIndex := 0
+	for i, r := range sarifRules {
+		if r.ID == ruleID {
+			ruleIndex = i
+			break
+		}
+	}
+
+	fqn := fmt.Sprintf("%s.%s", dd.Target.Database, od.Path())
+
+	loc := sarifLocation{
+		LogicalLocations: []sarifLogicalLocation{{
+			Name:               od.Name,
+			FullyQualifiedName: fqn,
+			Kind:               string(od.Object),
+		}},
+	}
+
+	// prefer the location within the drifted database, falling back to the baseline's definition of the object
+	origin := objectOrigin(target, od)
+	if origin == nil {
+		origin = objectOrigin(baseline, od)
+	}
+	if origin != nil {
+		loc.PhysicalLocation = &sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: sarifURI(origin.File)},
+			Region:           sarifRegion{StartLine: origin.Line},
+		}
+	}
+
+	return sarifResult{
+		RuleID:    ruleID,
+		RuleIndex: ruleIndex,
+		Level:     sarifRules[ruleIndex].Level,
+		Message:   sarifMessage{Text: fmt.Sprintf("%s: %s", dd.Target, od)},
+		Locations: []sarifLocation{loc},
+		// line numbers change as files are edited, so results are tracked by the object they concern
+		PartialFingerprints: map[string]string{
+			"schemaObject/v1": hashStrings(ruleID, dd.Target.Connection, fqn, od.Property),
+		},
+		Properties: map[string]string{
+			"baseline": dd.Baseline.String(),
+			"target":   dd.Target.String(),
+		},
+	}
+}
+
+func (sf *SARIFFormatter) Render(summaries connectionSummaries, sink io.Writer) error {
+	dbs := summaries.AllDatabases()
+	res := buildDiff(summaries)
+
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           "mysql-diff",
+				Version:        toolVersion(),
+				InformationURI: "https://github.com/dcarbone/mysql-diff",
+				Rules:          make([]sarifReportingDescriptor, 0, len(sarifRules)),
+			},
+		},
+		Results: make([]sarifResult, 0),
+	}
+
+	for _, r := range sarifRules {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifReportingDescriptor{
+			ID:                   r.ID,
+			ShortDescription:     sarifMessage{Text: r.Description},
+			DefaultConfiguration: sarifReportingConfiguration{Level: r.Level},
+		})
+	}
+
+	for i, dd := range res.Comparisons {
+		for _, od := range dd.Differences {
+			run.Results = append(run.Results, newSARIFResult(dd, od, dbs[0].Summary, dbs[i+1].Summary))
+		}
+	}
+
+	out := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	}
+
+	var (
+		b   []byte
+		err error
+	)
+	if sf.pretty {
+		b, err = json.MarshalIndent(out, "", "  ")
+	} else {
+		b, err = json.Marshal(out)
+	}
+	if err != nil {
+		return fmt.Errorf("error json-marshalling diff: %w", err)
+	}
+
+	if _, err = sink.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("error writing output: %w", err)
+	}
+
+	return nil
+}
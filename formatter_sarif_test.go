@@ -0,0 +1,17 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSARIFFormatter(t *testing.T) {
+	summaries := fixtureSummaries(t)
+
+	out := render(t, FormatSARIF, map[string]string{"pretty": "true"}, summaries)
+	if !json.Valid(out) {
+		t.Fatal("expected valid JSON")
+	}
+
+	assertGolden(t, "sarif.json", out)
+}
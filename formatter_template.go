@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	FormatTemplate = "template"
+)
+
+var _ Formatter = (*TemplateFormatter)(nil)
+
+// TemplateFormatter executes a user-supplied text/template against the diff and the summaries it was built from
+type TemplateFormatter struct {
+	tmpl  *template.Template
+	color string
+}
+
+// templateColors maps the color names accepted by the template "color" function to their ANSI codes
+var templateColors = map[string]string{
+	"bold":    ansiBold,
+	"red":     ansiRed,
+	"green":   ansiGreen,
+	"yellow":  "\x1b[33m",
+	"blue":    "\x1b[34m",
+	"magenta": "\x1b[35m",
+	"cyan":    ansiCyan,
+}
+
+// templateData is the value templates are executed against
+type templateData struct {
+	// Baseline is the database every other database is compared against
+	Baseline databaseRef
+	// Databases lists every summarized database, in order
+	Databases []summaryDatabase
+	// Comparisons contains the differences between the baseline and each other database
+	Comparisons []*databaseDiff
+	// Summaries contains the summaries of every connection
+	Summaries connectionSummaries
+}
+
+func newTemplateFormatter(_ *cli.Context, cfg map[string]string) (Formatter, error) {
+	tf := TemplateFormatter{
+		color: colorAuto,
+	}
+
+	path, ok := cfg["template"]
+	if !ok || path == "" {
+		return nil, errors.New("flag \"template\" must be provided")
+	}
+
+	if v, ok := cfg["color"]; ok {
+		switch v {
+		case colorAuto, colorAlways, colorNever:
+			tf.color = v
+		default:
+			return nil, fmt.Errorf("unknown \"color\" value %q specified, expected one of %v", v, []string{colorAlways, colorAuto, colorNever})
+		}
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading template %q: %w", path, err)
+	}
+
+	// the color function is replaced once the sink, and so whether it is a terminal, is known
+	if tf.tmpl, err = template.New(filepath.Base(path)).Funcs(templateFuncs(false)).Parse(string(b)); err != nil {
+		return nil, fmt.Errorf("error parsing template %q: %w", path, err)
+	}
+
+	return &tf, nil
+}
+
+func (*TemplateFormatter) Type() string {
+	return FormatTemplate
+}
+
+// templateJoin joins the elements of any slice with the provided separator, formatting each element with fmt.Sprint
+func templateJoin(sep string, elems any) (string, error) {
+	if ss, ok := elems.([]string); ok {
+		return strings.Join(ss, sep), nil
+	}
+
+	rv := reflect.ValueOf(elems)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return "", fmt.Errorf("join expects a slice, saw %T", elems)
+	}
+
+	parts := make([]string, rv.Len())
+	for i := range parts {
+		parts[i] = fmt.Sprint(rv.Index(i).Interface())
+	}
+	return strings.Join(parts, sep), nil
+}
+
+// templateFuncs returns the functions available to templates.  When color is false the "color" function returns its
+// text unchanged.
+func templateFuncs(color bool) template.FuncMap {
+	return template.FuncMap{
+		// kind filters differences by change kind: added, removed or changed
+		"kind": func(kind string, diffs []objectDiff) []objectDiff {
+			return filterDiffs(diffs, func(od objectDiff) bool { return string(od.Kind) == kind })
+		},
+		"added": func(diffs []objectDiff) []objectDiff {
+			return filterDiffs(diffs, func(od objectDiff) bool { return od.Kind == changeAdded })
+		},
+		"removed": func(diffs []objectDiff) []objectDiff {
+			return filterDiffs(diffs, func(od objectDiff) bool { return od.Kind == changeRemoved })
+		},
+		"changed": func(diffs []objectDiff) []objectDiff {
+			return filterDiffs(diffs, func(od objectDiff) bool { return od.Kind == changeChanged })
+		},
+		// object filters differences by object kind, e.g. table, column or index
+		"object": func(object string, diffs []objectDiff) []objectDiff {
+			return filterDiffs(diffs, func(od objectDiff) bool { return string(od.Object) == object })
+		},
+		"join":        templateJoin,
+		"quote":       quoteIdent,
+		"quoteString": quoteString,
+		"upper":       strings.ToUpper,
+		"lower":       strings.ToLower,
+		"color": func(name, text string) (string, error) {
+			code, ok := templateColors[name]
+			if !ok {
+				return "", fmt.Errorf("unknown color %q", name)
+			}
+			if !color {
+				return text, nil
+			}
+			return code + text + ansiReset, nil
+		},
+	}
+}
+
+func (tf *TemplateFormatter) Render(summaries connectionSummaries, sink io.Writer) error {
+	res := buildDiff(summaries)
+
+	data := templateData{
+		Baseline:    res.Baseline,
+		Databases:   summaries.AllDatabases(),
+		Comparisons: res.Comparisons,
+		Summaries:   summaries,
+	}
+
+	tmpl := tf.tmpl.Funcs(templateFuncs(tf.color == colorAlways || (tf.color == colorAuto && isTerminal(sink))))
+
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return fmt.Errorf("error executing template: %w", err)
+	}
+
+	if _, err := sink.Write(b.Bytes()); err != nil {
+		return fmt.Errorf("error writing output: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTemplateFormatterGolden(t *testing.T) {
+	summaries := fixtureSummaries(t)
+	path := filepath.Join("testdata", "template", "drift.tmpl")
+
+	assertGolden(t, "template.txt", render(t, FormatTemplate, map[string]string{"template": path, "color": "never"}, summaries))
+	assertGolden(t, "template-color.txt", render(t, FormatTemplate, map[string]string{"template": path, "color": "always"}, summaries))
+}
+
+func TestTemplateFormatterAutoColor(t *testing.T) {
+	path := filepath.Join("testdata", "template", "drift.tmpl")
+
+	// a buffer is never a terminal
+	got := render(t, FormatTemplate, map[string]string{"template": path}, fixtureSummaries(t))
+	if bytes.Contains(got, []byte("\x1b[")) {
+		t.Errorf("expected no color codes when not writing to a terminal, got:\n%s", got)
+	}
+}
+
+func TestTemplateFormatterErrors(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, src string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(src), 0666); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	valid := write("valid.tmpl", "{{ .Baseline }}")
+
+	t.Run("config", func(t *testing.T) {
+		tests := map[string]map[string]string{
+			"missing template": {},
+			"empty template":   {"template": ""},
+			"unknown color":    {"template": valid, "color": "sometimes"},
+			"unreadable":       {"template": filepath.Join(dir, "missing.tmpl")},
+			"unparsable":       {"template": write("bad.tmpl", "{{ range }")},
+			"unknown function": {"template": write("func.tmpl", "{{ shout .Baseline }}")},
+		}
+
+		for name, cfg := range tests {
+			if _, err := newTemplateFormatter(nil, cfg); err == nil {
+				t.Errorf("%s: expected an error", name)
+			}
+		}
+	})
+
+	t.Run("execution", func(t *testing.T) {
+		tests := map[string]string{
+			"unknown color":  `{{ color "mauve" "text" }}`,
+			"join non-slice": `{{ join ", " .Baseline }}`,
+			"missing field":  `{{ .Nope }}`,
+		}
+
+		for name, src := range tests {
+			f, err := newTemplateFormatter(nil, map[string]string{"template": write("exec.tmpl", src)})
+			if err != nil {
+				t.Fatalf("%s: error building formatter: %v", name, err)
+			}
+			var b bytes.Buffer
+			if err = f.Render(fixtureSummaries(t), &b); err == nil || !strings.Contains(err.Error(), "error executing template") {
+				t.Errorf("%s: expected an execution error, saw %v", name, err)
+			}
+		}
+	})
+}
+
+func TestTemplateJoin(t *testing.T) {
+	tests := []struct {
+		elems any
+		want  string
+	}{
+		{[]string{"a", "b"}, "a, b"},
+		{[]int{1, 2, 3}, "1, 2, 3"},
+		{[2]changeKind{changeAdded, changeRemoved}, "added, removed"},
+		{[]string{}, ""},
+	}
+
+	for _, tt := range tests {
+		got, err := templateJoin(", ", tt.elems)
+		if err != nil {
+			t.Errorf("join(%v): unexpected error: %v", tt.elems, err)
+		} else if got != tt.want {
+			t.Errorf("join(%v): got %q, want %q", tt.elems, got, tt.want)
+		}
+	}
+}
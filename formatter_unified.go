@@ -0,0 +1,225 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	FormatUnified = "unified"
+)
+
+const (
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+)
+
+const (
+	ansiReset = "\x1b[0m"
+	ansiBold  = "\x1b[1m"
+	ansiRed   = "\x1b[31m"
+	ansiGreen = "\x1b[32m"
+	ansiCyan  = "\x1b[36m"
+)
+
+var _ Formatter = (*UnifiedFormatter)(nil)
+
+// UnifiedFormatter renders a unified diff between the canonical CREATE statement of every object in the baseline and
+// in each other database.
+type UnifiedFormatter struct {
+	context int
+	color   string
+}
+
+func newUnifiedFormatter(_ *cli.Context, cfg map[string]string) (Formatter, error) {
+	var err error
+
+	uf := UnifiedFormatter{
+		context: 3,
+		color:   colorAuto,
+	}
+
+	if v, ok := cfg["context"]; ok {
+		if uf.context, err = strconv.Atoi(v); err != nil {
+			return nil, fmt.Errorf("error parsing flag \"context\" value %q as int: %w", v, err)
+		}
+		if uf.context < 0 {
+			return nil, fmt.Errorf("flag \"context\" must not be negative, saw %d", uf.context)
+		}
+	}
+
+	if v, ok := cfg["color"]; ok {
+		switch v {
+		case colorAuto, colorAlways, colorNever:
+			uf.color = v
+		default:
+			return nil, fmt.Errorf("unknown \"color\" value %q specified, expected one of %v", v, []string{colorAlways, colorAuto, colorNever})
+		}
+	}
+
+	return &uf, nil
+}
+
+func (*UnifiedFormatter) Type() string {
+	return FormatUnified
+}
+
+// isTerminal returns true if the writer is a terminal.  The NO_COLOR convention is honored by reporting false when it
+// is set.
+func isTerminal(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// canonicalObject is the canonical CREATE statement of a single object.  Tables and views share a namespace, so both
+// are keyed as tables.
+type canonicalObject struct {
+	Object objectKind
+	Label  string
+	Name   string
+	Text   string
+}
+
+type canonicalKey struct {
+	object objectKind
+	name   string
+}
+
+// canonicalObjects renders every object of a database, keyed by object kind and name
+func canonicalObjects(ds *databaseSummary) map[canonicalKey]canonicalObject {
+	out := make(map[canonicalKey]canonicalObject)
+
+	for _, ts := range ds.Tables {
+		label := "table"
+		if ts.Type == tableTypeView {
+			label = "view"
+		}
+		out[canonicalKey{objectTable, ts.Name}] = canonicalObject{objectTable, label, ts.Name, canonicalTable(*ts, ds.Name)}
+	}
+	for _, rs := range ds.Routines {
+		obj := routineObjects[rs.Type]
+		out[canonicalKey{obj, rs.Name}] = canonicalObject{obj, string(obj), rs.Name, canonicalRoutine(*rs)}
+	}
+	for _, ts := range ds.Triggers {
+		out[canonicalKey{objectTrigger, ts.Name}] = canonicalObject{objectTrigger, string(objectTrigger), ts.Name, canonicalTrigger(*ts)}
+	}
+
+	return out
+}
+
+// canonicalOrder lists the object kinds in the order they are rendered
+var canonicalOrder = []objectKind{objectTable, objectProcedure, objectFunction, objectTrigger}
+
+// sortedCanonicalKeys returns the union of the keys of both maps, ordered by object kind and then name
+func sortedCanonicalKeys(a, b map[canonicalKey]canonicalObject) []canonicalKey {
+	out := make([]canonicalKey, 0, len(a)+len(b))
+	for k := range a {
+		out = append(out, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			out = append(out, k)
+		}
+	}
+	slices.SortFunc(out, func(x, y canonicalKey) int {
+		if c := slices.Index(canonicalOrder, x.object) - slices.Index(canonicalOrder, y.object); c != 0 {
+			return c
+		}
+		return strings.Compare(x.name, y.name)
+	})
+	return out
+}
+
+// changedCanonicalKeys returns the keys of the objects with differences.  Columns, indexes and foreign keys are
+// rendered as part of their table.
+func changedCanonicalKeys(diffs []objectDiff) map[canonicalKey]bool {
+	out := make(map[canonicalKey]bool)
+	for _, od := range diffs {
+		switch od.Object {
+		case objectColumn, objectIndex, objectForeignKey:
+			out[canonicalKey{objectTable, od.Parent}] = true
+		default:
+			out[canonicalKey{od.Object, od.Name}] = true
+		}
+	}
+	return out
+}
+
+func (uf *UnifiedFormatter) Render(summaries connectionSummaries, sink io.Writer) error {
+	dbs := summaries.AllDatabases()
+	res := buildDiff(summaries)
+
+	color := uf.color == colorAlways || (uf.color == colorAuto && isTerminal(sink))
+	paint := func(code, line string) string {
+		if !color {
+			return line
+		}
+		return code + line + ansiReset
+	}
+
+	var b strings.Builder
+
+	if len(dbs) > 0 {
+		// only objects the diff reports as changed are rendered, but from the summaries as read, so that columns are
+		// listed in their defined order
+		base := canonicalObjects(dbs[0].Summary)
+
+		for i, dd := range res.Comparisons {
+			target := canonicalObjects(dbs[i+1].Summary)
+			changed := changedCanonicalKeys(dd.Differences)
+
+			for _, k := range sortedCanonicalKeys(base, target) {
+				bo, bok := base[k]
+				tgt, tok := target[k]
+				if !changed[k] || bo.Text == tgt.Text {
+					continue
+				}
+
+				oldName, newName := "/dev/null", "/dev/null"
+				if bok {
+					oldName = fmt.Sprintf("%s %s %s", dd.Baseline, bo.Label, quoteIdent(bo.Name))
+				}
+				if tok {
+					newName = fmt.Sprintf("%s %s %s", dd.Target, tgt.Label, quoteIdent(tgt.Name))
+				}
+
+				b.WriteString(paint(ansiBold, "--- "+oldName) + "\n")
+				b.WriteString(paint(ansiBold, "+++ "+newName) + "\n")
+
+				for _, hunk := range unifiedHunks(diffLines(splitLines(bo.Text), splitLines(tgt.Text)), uf.context) {
+					for _, line := range hunk {
+						switch line[0] {
+						case '@':
+							line = paint(ansiCyan, line)
+						case '-':
+							line = paint(ansiRed, line)
+						case '+':
+							line = paint(ansiGreen, line)
+						}
+						b.WriteString(line + "\n")
+					}
+				}
+			}
+		}
+	}
+
+	if _, err := sink.Write([]byte(b.String())); err != nil {
+		return fmt.Errorf("error writing output: %w", err)
+	}
+
+	return nil
+}
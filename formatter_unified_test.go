@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestUnifiedFormatter(t *testing.T) {
+	summaries := fixtureSummaries(t)
+
+	tests := []struct {
+		golden string
+		cfg    map[string]string
+	}{
+		{"unified.diff", map[string]string{"color": "never"}},
+		{"unified-color.diff", map[string]string{"color": "always", "context": "0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			assertGolden(t, tt.golden, render(t, FormatUnified, tt.cfg, summaries))
+		})
+	}
+}
+
+func TestUnifiedFormatterNormalized(t *testing.T) {
+	summary := func(label, routine, trigger string) *connectionSummary {
+		return &connectionSummary{Label: label, Databases: []*databaseSummary{{
+			Name:     "shop",
+			Tables:   []*tableSummary{{Name: "t", Type: "BASE TABLE", Columns: []columnSummary{{Name: "id", Type: "int", Nullable: "NO"}}}},
+			Routines: []*routineSummary{{Name: "p", Type: "PROCEDURE", Definition: routine}},
+			Triggers: []*triggerSummary{{Name: "tr", Table: "t", Timing: "BEFORE", Event: "INSERT", Statement: trigger}},
+		}}}
+	}
+
+	// objects differing only by whitespace are not reported by diff, so must not be rendered
+	summaries := connectionSummaries{
+		summary("a", "BEGIN\n  SELECT 1;\nEND", "SET NEW.id = 1"),
+		summary("b", "BEGIN SELECT 1; END", "SET  NEW.id = 1\n"),
+	}
+
+	if out := render(t, FormatUnified, map[string]string{"color": "never"}, summaries); len(out) != 0 {
+		t.Errorf("expected no output, got:\n%s", out)
+	}
+}
@@ -3,9 +3,12 @@ module github.com/dcarbone/mysql-diff
 go 1.24.0
 
 require (
+	github.com/BurntSushi/toml v1.6.0
 	github.com/go-sql-driver/mysql v1.10.0
 	github.com/jedib0t/go-pretty/v6 v6.8.0
+	github.com/klauspost/compress v1.18.0
 	github.com/urfave/cli/v2 v2.27.7
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
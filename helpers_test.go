@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files with the current output")
+
+// ddlSummary summarizes a DDL file within testdata/schema, labelling the connection with label
+func ddlSummary(t *testing.T, label, file string) *connectionSummary {
+	t.Helper()
+
+	ds := &ddlSource{Label: label, Path: filepath.Join("testdata", "schema", file)}
+	summaries, err := ds.Summarize(context.Background())
+	if err != nil {
+		t.Fatalf("error summarizing %q: %v", file, err)
+	}
+
+	return summaries[0]
+}
+
+// fixtureSummaries returns the summaries of the base and target schemas, in that order
+func fixtureSummaries(t *testing.T) connectionSummaries {
+	t.Helper()
+	return connectionSummaries{ddlSummary(t, "base", "base.sql"), ddlSummary(t, "target", "target.sql")}
+}
+
+// assertGolden compares got with the named file within testdata/golden, rewriting it when -update is set
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", "golden", name)
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0666); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("error reading golden file %q (run with -update to create it): %v", path, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output does not match %q (run with -update to accept it)\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+// render renders summaries with the named formatter and config
+func render(t *testing.T, format string, cfg map[string]string, summaries connectionSummaries) []byte {
+	t.Helper()
+
+	f, err := formatters[format](nil, cfg)
+	if err != nil {
+		t.Fatalf("error building %q formatter: %v", format, err)
+	}
+
+	var b bytes.Buffer
+	if err = f.Render(summaries, &b); err != nil {
+		t.Fatalf("error rendering %q: %v", format, err)
+	}
+
+	return b.Bytes()
+}
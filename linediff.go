@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// lineOp is a single line of a line-based diff.  Op is ' ' for a line common to both sides, '-' for a line only in the
+// old text, and '+' for a line only in the new text.
+type lineOp struct {
+	Op   byte
+	Text string
+}
+
+// splitLines splits text into lines, ignoring carriage returns.  Empty text has no lines.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
+
+// diffLines returns the shortest edit script turning a into b, computed from their longest common subsequence
+func diffLines(a, b []string) []lineOp {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	out := make([]lineOp, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			out = append(out, lineOp{' ', a[i]})
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			out = append(out, lineOp{'-', a[i]})
+			i++
+		default:
+			out = append(out, lineOp{'+', b[j]})
+			j++
+		}
+	}
+
+	return out
+}
+
+// hunkRange formats one side of a hunk header the way GNU diff does: the count is omitted when it is one, and an empty
+// range starts at the line preceding it.
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, count)
+	}
+}
+
+// unifiedHunks groups a diff into hunks with the provided number of context lines, returning the lines of each hunk
+// including its "@@" header.  Identical texts produce no hunks.
+func unifiedHunks(ops []lineOp, context int) [][]string {
+	// aPos and bPos are the number of old and new lines preceding each op
+	aPos, bPos := make([]int, len(ops)+1), make([]int, len(ops)+1)
+	changes := make([]int, 0)
+	for i, op := range ops {
+		aPos[i+1], bPos[i+1] = aPos[i], bPos[i]
+		if op.Op != '+' {
+			aPos[i+1]++
+		}
+		if op.Op != '-' {
+			bPos[i+1]++
+		}
+		if op.Op != ' ' {
+			changes = append(changes, i)
+		}
+	}
+
+	out := make([][]string, 0)
+	for c := 0; c < len(changes); {
+		// extend the hunk while the next change is close enough for the context lines to overlap
+		last := c
+		for last+1 < len(changes) && changes[last+1]-changes[last] <= 2*context+1 {
+			last++
+		}
+
+		from := max(changes[c]-context, 0)
+		to := min(changes[last]+context+1, len(ops))
+
+		hunk := []string{fmt.Sprintf(
+			"@@ -%s +%s @@",
+			hunkRange(aPos[from], aPos[to]-aPos[from]),
+			hunkRange(bPos[from], bPos[to]-bPos[from]),
+		)}
+		for _, op := range ops[from:to] {
+			hunk = append(hunk, string(op.Op)+op.Text)
+		}
+		out = append(out, hunk)
+
+		c = last + 1
+	}
+
+	return out
+}
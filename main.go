@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"runtime/debug"
 	"slices"
 
 	_ "github.com/go-sql-driver/mysql"
@@ -11,6 +12,10 @@ import (
 
 const (
 	flagConn         = "conn"
+	flagSnapshot     = "snapshot"
+	flagDDL          = "ddl"
+	flagMigrations   = "migrations"
+	flagBaseline     = "baseline"
 	flagPretty       = "pretty"
 	flagFormat       = "format"
 	flagFormatConfig = "format-config"
@@ -18,47 +23,170 @@ const (
 	flagOutConfig    = "out-config"
 )
 
-func preRun(cctx *cli.Context) (mysqlConns, error) {
+// version is set at build time with -ldflags "-X main.version=$version"
+var version = ""
+
+// toolVersion returns the version of this build, falling back to the module version recorded by the go toolchain
+func toolVersion() string {
+	if version != "" {
+		return version
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok && bi.Main.Version != "" {
+		return bi.Main.Version
+	}
+	return "(devel)"
+}
+
+func preRun(cctx *cli.Context) (summarySources, error) {
 	connConfigs, err := parseConnFlags(cctx)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing flags: %w", err)
 	}
 
+	snapshots, err := parseSnapshotFlags(cctx)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing flags: %w", err)
+	}
+
+	ddls, err := parseDDLFlags(cctx)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing flags: %w", err)
+	}
+
+	if len(connConfigs)+len(snapshots)+len(ddls) == 0 {
+		return nil, fmt.Errorf("at least one %q, %q, %q, or %q source must be provided", flagConn, flagSnapshot, flagDDL, flagMigrations)
+	}
+
 	conns, err := openConnections(connConfigs)
 	if err != nil {
 		return nil, fmt.Errorf("error opening connections: %w", err)
 	}
 
-	return conns, nil
+	sources := make(summarySources, 0, len(conns)+len(snapshots)+len(ddls))
+	for _, conn := range conns {
+		sources = append(sources, conn)
+	}
+	for _, snap := range snapshots {
+		sources = append(sources, snap)
+	}
+	for _, ddl := range ddls {
+		sources = append(sources, ddl)
+	}
+
+	return sources, nil
+}
+
+// outFlag returns the flag selecting the output the named command writes to
+func outFlag(command string) *cli.StringFlag {
+	return &cli.StringFlag{
+		Name:        flagOut,
+		Usage:       fmt.Sprintf("Destination of formatted %s.  Available outputs: %v", command, AvailableOutputs()),
+		Value:       OutputStdOut,
+		DefaultText: OutputStdOut,
+		Action: func(_ *cli.Context, v string) error {
+			available := AvailableOutputs()
+			if !slices.Contains(available, v) {
+				return fmt.Errorf("unknown output %q specified, expected to be one of: %v", v, available)
+			}
+			return nil
+		},
+	}
+}
+
+func outConfigFlag() *MapStringFlag {
+	return &MapStringFlag{
+		Name:     flagOutConfig,
+		Usage:    `Configuration map for the specified output.  Available keys depend on output.  Must follow structure: "key=value,key2=value2"`,
+		Required: false,
+	}
 }
 
 func main() {
 	app := &cli.App{
+		Version: toolVersion(),
 		Flags: []cli.Flag{
 			&cli.StringSliceFlag{
 				Name:     flagConn,
 				Aliases:  []string{"C"},
 				Usage:    "A single MySQL connection with structure: \"addr=$addr user=$user pass=$pass db=$db[ db=$dbX][ label=$label]\"",
-				Required: true,
+				Required: false,
+			},
+			&cli.StringSliceFlag{
+				Name:     flagSnapshot,
+				Aliases:  []string{"S"},
+				Usage:    "A summary file previously produced by the \"summary\" command, with structure: \"[$label=]$path\"",
+				Required: false,
+			},
+			&cli.StringSliceFlag{
+				Name:     flagDDL,
+				Aliases:  []string{"D"},
+				Usage:    "A SQL file, or directory of .sql files, containing CREATE statements, with structure: \"path=$path[ db=$db][ db=$dbX][ label=$label]\"",
+				Required: false,
+			},
+			&cli.StringSliceFlag{
+				Name:     flagMigrations,
+				Aliases:  []string{"M"},
+				Usage:    "A directory of golang-migrate, goose, Flyway, or Liquibase SQL migrations, with structure: \"path=$path[ db=$db][ db=$dbX][ label=$label]\"",
+				Required: false,
 			},
 		},
 		Commands: cli.Commands{
 			{
 				Name:  "summary",
-				Usage: "Produce a JSON summary of all configured database schemas",
+				Usage: "Produce a JSON, YAML, or TOML summary of all configured database schemas",
 				Flags: []cli.Flag{
 					&cli.BoolFlag{
 						Name:  flagPretty,
-						Usage: "If provided, produces formatted JSON output",
+						Usage: "If provided, produces formatted JSON output.  Only valid with the json format",
 					},
+
+					// formatter and config
+					&cli.StringFlag{
+						Name:        flagFormat,
+						Usage:       fmt.Sprintf("Encoding of the summary.  Available formatters: %v", AvailableSummaryFormatters()),
+						Value:       SummaryFormatJSON,
+						DefaultText: SummaryFormatJSON,
+						Action: func(_ *cli.Context, v string) error {
+							available := AvailableSummaryFormatters()
+							if !slices.Contains(available, v) {
+								return fmt.Errorf("unknown formatter %q specified, expected one of: %v", v, available)
+							}
+							return nil
+						},
+					},
+					&MapStringFlag{
+						Name:     flagFormatConfig,
+						Usage:    `Configuration map for the specified formatter.  Available keys depend on formatter.  Must follow structure: "key=value,key2=value2"`,
+						Required: false,
+					},
+
+					// output and config
+					outFlag("summary"),
+					outConfigFlag(),
 				},
 				Action: summaryRun,
 			},
+			{
+				Name:  "fingerprint",
+				Usage: "Produce SHA-256 fingerprints of every configured database, table, and routine",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  flagPretty,
+						Usage: "If provided, produces formatted JSON output",
+					},
+				},
+				Action: fingerprintRun,
+			},
 			{
 				Name:   "diff",
 				Usage:  "Produce a diff of the database summaries",
 				Action: diffRun,
 				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  flagBaseline,
+						Usage: "Label of the source every other database is compared against.  Defaults to the first source, taking every -conn source before any -snapshot, -ddl, or -migrations source",
+					},
+
 					// formatter and config
 					&cli.StringFlag{
 						Name:        flagFormat,
@@ -80,22 +208,13 @@ func main() {
 					},
 
 					// output and config
-					&cli.StringFlag{
-						Name:        flagOut,
-						Usage:       fmt.Sprintf("Destination of formatted diff.  Available outputs: %v", AvailableOutputs()),
-						Value:       OutputStdOut,
-						DefaultText: OutputStdOut,
-						Action: func(_ *cli.Context, v string) error {
-							available := AvailableOutputs()
-							if !slices.Contains(available, v) {
-								return fmt.Errorf("unknown output %q specified, expected to be one of: %v", v, available)
-							}
-							return nil
-						},
-					},
-					&MapStringFlag{
-						Name:     flagOutConfig,
-						Usage:    `Configuration map for the specified output.  Available keys depend on output.  Must follow structure: "key=value,key2=value2"`,
+					outFlag("diff"),
+					outConfigFlag(),
+
+					// additional formatter and output pairs
+					&cli.StringSliceFlag{
+						Name:     flagPipe,
+						Usage:    `An additional formatter and output to render the diff with, with structure: "format=$format out=$out[ format.$key=$value][ out.$key=$value]"`,
 						Required: false,
 					},
 				},
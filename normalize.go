@@ -0,0 +1,104 @@
+package main
+
+import (
+	"slices"
+	"strings"
+)
+
+// normalizeDatabase returns a copy of the provided database summary with all order-dependent lists sorted, so that two
+// summaries of identical schemas compare equal regardless of the order in which objects were read.
+func normalizeDatabase(ds *databaseSummary) *databaseSummary {
+	out := &databaseSummary{
+		Name:     ds.Name,
+		Tables:   make([]*tableSummary, len(ds.Tables)),
+		Routines: make([]*routineSummary, len(ds.Routines)),
+		Triggers: make([]*triggerSummary, len(ds.Triggers)),
+	}
+
+	for i, tbl := range ds.Tables {
+		out.Tables[i] = normalizeTable(tbl, ds.Name)
+	}
+
+	for i, t := range ds.Triggers {
+		out.Triggers[i] = normalizeTrigger(t)
+	}
+
+	for i, r := range ds.Routines {
+		out.Routines[i] = normalizeRoutine(r)
+	}
+
+	slices.SortFunc(out.Tables, func(a, b *tableSummary) int { return strings.Compare(a.Name, b.Name) })
+	slices.SortFunc(out.Triggers, func(a, b *triggerSummary) int { return strings.Compare(a.Name, b.Name) })
+	slices.SortFunc(out.Routines, func(a, b *routineSummary) int {
+		if c := strings.Compare(a.Type, b.Type); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return out
+}
+
+// normalizeTable returns a sorted copy of the provided table without source locations.  View definitions are
+// normalized, as MySQL fully qualifies every reference in the definitions it reports.
+func normalizeTable(ts *tableSummary, db string) *tableSummary {
+	out := &tableSummary{
+		Name:       ts.Name,
+		Type:       ts.Type,
+		Definition: normalizeViewDefinition(ts.Definition, db),
+		Verbatim:   ts.Verbatim,
+		Columns:    make([]columnSummary, len(ts.Columns)),
+	}
+
+	for i, c := range ts.Columns {
+		c.Origin = nil
+		out.Columns[i] = c
+	}
+
+	// a nil index or foreign key list means they are unknown, and must remain distinguishable from a table without any
+	if ts.Indexes != nil {
+		out.Indexes = make([]indexSummary, len(ts.Indexes))
+		for i, idx := range ts.Indexes {
+			idx.Origin = nil
+			out.Indexes[i] = idx
+		}
+	}
+	if ts.ForeignKeys != nil {
+		out.ForeignKeys = make([]foreignKeySummary, len(ts.ForeignKeys))
+		for i, fk := range ts.ForeignKeys {
+			fk.Origin = nil
+			out.ForeignKeys[i] = fk
+		}
+	}
+
+	slices.SortFunc(out.Columns, func(a, b columnSummary) int { return strings.Compare(a.Name, b.Name) })
+	slices.SortFunc(out.Indexes, func(a, b indexSummary) int { return strings.Compare(a.Name, b.Name) })
+	slices.SortFunc(out.ForeignKeys, func(a, b foreignKeySummary) int { return strings.Compare(a.Name, b.Name) })
+
+	return out
+}
+
+func normalizeRoutine(rs *routineSummary) *routineSummary {
+	out := *rs
+	out.Definition = collapseWhitespace(rs.Definition)
+	out.Origin = nil
+	return &out
+}
+
+// normalizeViewDefinition collapses the whitespace of a view definition and removes any qualification with the name of
+// the view's own database
+func normalizeViewDefinition(def, db string) string {
+	return collapseWhitespace(strings.ReplaceAll(def, "`"+db+"`.", ""))
+}
+
+// collapseWhitespace trims the input and replaces every run of whitespace with a single space
+func collapseWhitespace(in string) string {
+	return strings.Join(strings.Fields(in), " ")
+}
+
+func normalizeTrigger(ts *triggerSummary) *triggerSummary {
+	out := *ts
+	out.Statement = collapseWhitespace(ts.Statement)
+	out.Origin = nil
+	return &out
+}
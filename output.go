@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"io"
 	"os"
 	"slices"
@@ -25,6 +26,10 @@ func init() {
 		OutputStdOut: func(_ *cli.Context, _ map[string]string) (Output, error) { return stdOut, nil },
 		OutputStdErr: func(_ *cli.Context, _ map[string]string) (Output, error) { return stdErr, nil },
 		OutputFile:   newFileOutput,
+		OutputHTTP:   newHTTPOutput,
+		OutputGit:    newGitOutput,
+		OutputSyslog: newSyslogOutput,
+		OutputChat:   newChatOutput,
 	}
 }
 
@@ -39,8 +44,81 @@ func AvailableOutputs() []string {
 	return out
 }
 
+// publishOutput publishes the content written to the writer if it is a Publisher
+func publishOutput(w io.Writer) error {
+	if p, ok := w.(Publisher); ok {
+		if err := p.Publish(); err != nil {
+			return fmt.Errorf("error publishing output: %w", err)
+		}
+	}
+	return nil
+}
+
+// renderOutput opens a writer for the output, renders into it and publishes the result
+func renderOutput(out Output, render func(w io.Writer) error) error {
+	w, err := out.Writer()
+	if err != nil {
+		return fmt.Errorf("error opening writer for output: %w", err)
+	}
+
+	// if this is a closeable writer, queue up close.
+	if wc, ok := w.(io.Closer); ok {
+		defer func() { _ = wc.Close() }()
+	}
+
+	if err = render(w); err != nil {
+		return err
+	}
+
+	return publishOutput(w)
+}
+
+// renderSummaries renders the summaries into the output, informing it of the summaries rendered
+func renderSummaries(out Output, summaries connectionSummaries, render func(connectionSummaries, io.Writer) error) error {
+	if sa, ok := out.(summariesAware); ok {
+		sa.setSummaries(summaries)
+	}
+	return renderOutput(out, func(w io.Writer) error { return render(summaries, w) })
+}
+
+// renderDatabases renders the summaries into the output.  Outputs splitting by database render once for every
+// database, with the summaries limited to that database, and to the baseline as well if withBaseline is set.  The
+// baseline is not rendered on its own when there are other databases to compare it with.
+func renderDatabases(out Output, summaries connectionSummaries, withBaseline bool, render func(connectionSummaries, io.Writer) error) error {
+	dbs := summaries.AllDatabases()
+
+	ds, ok := out.(databaseSplitter)
+	if !ok || len(dbs) == 0 {
+		return renderSummaries(out, summaries, render)
+	}
+
+	if !ds.splitting() {
+		ds.setDatabase(dbs[0].Ref)
+		return renderSummaries(out, summaries, render)
+	}
+
+	baseline, targets := dbs[0], dbs
+	if withBaseline && len(dbs) > 1 {
+		targets = dbs[1:]
+	}
+
+	for _, db := range targets {
+		only := []*databaseSummary{db.Summary}
+		if withBaseline {
+			only = append(only, baseline.Summary)
+		}
+
+		ds.setDatabase(db.Ref)
+		if err := renderSummaries(out, summaries.Only(only...), render); err != nil {
+			return fmt.Errorf("error rendering %s: %w", db.Ref, err)
+		}
+	}
+
+	return nil
+}
+
 func BuildOutput(cctx *cli.Context) (Output, error) {
-	return outputs[cctx.String(flagOut)](cctx, cctx.Value(flagOutConfig).(MapString))
+	return newOutput(cctx, cctx.String(flagOut), cctx.String(flagFormat), cctx.Value(flagOutConfig).(MapString))
 }
 
 type Output interface {
@@ -50,6 +128,28 @@ type Output interface {
 
 type OutputConstructor func(*cli.Context, map[string]string) (Output, error)
 
+// Publisher is implemented by output writers which deliver their content only once it has been rendered in full, such
+// as the http output.  Publish is called after rendering succeeds, and never if it fails.
+type Publisher interface {
+	Publish() error
+}
+
+// databaseSplitter is implemented by outputs whose destination depends on the database rendered, and which may write
+// the result for each database separately.
+type databaseSplitter interface {
+	// splitting returns true if the result for each database should be written separately
+	splitting() bool
+	// setDatabase sets the database named by the destination of the next writer
+	setDatabase(ref databaseRef)
+}
+
+// summariesAware is implemented by outputs which write events derived from the summaries, rather than the rendered
+// result, such as the syslog output.
+type summariesAware interface {
+	// setSummaries sets the summaries rendered into the next writer
+	setSummaries(summaries connectionSummaries)
+}
+
 var (
 	_ Output = (*StdOutOutput)(nil)
 	_ Output = (*StdErrOutput)(nil)
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+const OutputChat = "chat"
+
+const (
+	chatStyleBlocks      = "blocks"
+	chatStyleAttachments = "attachments"
+)
+
+// chatHeaderLimit and chatSectionLimit are the maximum lengths of the text of header and section blocks
+const (
+	chatHeaderLimit  = 150
+	chatSectionLimit = 3000
+)
+
+var (
+	_ Output         = (*ChatOutput)(nil)
+	_ summariesAware = (*ChatOutput)(nil)
+	_ Publisher      = (*chatWriter)(nil)
+)
+
+// ChatOutput posts a condensed summary of the differences found to a Slack or Mattermost compatible incoming webhook
+// once rendering has completed.  The rendered result itself is discarded.  Requests are sent by the http output, and
+// accept its options.
+type ChatOutput struct {
+	http      *HTTPOutput
+	style     string
+	title     string
+	top       int
+	reportURL string
+	always    bool
+	channel   string
+	username  string
+	iconEmoji string
+
+	summaries connectionSummaries
+}
+
+func newChatOutput(cctx *cli.Context, cfg map[string]string) (Output, error) {
+	co := ChatOutput{
+		style: chatStyleBlocks,
+		title: "Schema drift check",
+		top:   10,
+	}
+
+	if _, ok := cfg["url"]; !ok {
+		return nil, fmt.Errorf("output %q requires config key %q to be set", OutputChat, "url")
+	}
+
+	ho, err := newHTTPOutput(cctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	co.http = ho.(*HTTPOutput)
+	co.http.contentType, co.http.contentTypeSet = "application/json", true
+
+	if v, ok := cfg["style"]; ok {
+		switch v {
+		case chatStyleBlocks, chatStyleAttachments:
+			co.style = v
+		default:
+			return nil, fmt.Errorf("unknown \"style\" value %q specified, expected one of %v", v, []string{chatStyleBlocks, chatStyleAttachments})
+		}
+	}
+
+	if v, ok := cfg["title"]; ok && v != "" {
+		co.title = v
+	}
+
+	if v, ok := cfg["top"]; ok {
+		if co.top, err = strconv.Atoi(v); err != nil {
+			return nil, fmt.Errorf("unable to parse \"top\" value %q as int: %w", v, err)
+		} else if co.top < 0 {
+			return nil, fmt.Errorf("\"top\" must not be negative, saw %d", co.top)
+		}
+	}
+
+	if v, ok := cfg["report-url"]; ok {
+		co.reportURL = v
+	}
+
+	if v, ok := cfg["always"]; ok {
+		if co.always, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("unable to parse \"always\" value %q as bool: %w", v, err)
+		}
+	}
+
+	co.channel = cfg["channel"]
+	co.username = cfg["username"]
+	co.iconEmoji = cfg["icon-emoji"]
+
+	return &co, nil
+}
+
+func (*ChatOutput) Type() string {
+	return OutputChat
+}
+
+// setSummaries sets the summaries the notification is built from
+func (c *ChatOutput) setSummaries(summaries connectionSummaries) {
+	c.summaries = summaries
+}
+
+func (c *ChatOutput) Writer() (io.Writer, error) {
+	return &chatWriter{out: c, summaries: c.summaries}, nil
+}
+
+// chatEscape escapes the characters with special meaning in message text
+func chatEscape(v string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(v)
+}
+
+// chatCounts returns a line counting the differing objects of a database
+func chatCounts(dd *databaseDiff) string {
+	added, removed, changed := dd.Counts()
+	if added+removed+changed == 0 {
+		return "no differences"
+	}
+	return fmt.Sprintf("%d added, %d removed, %d changed", added, removed, changed)
+}
+
+// chatSummary is the condensed content of a notification
+type chatSummary struct {
+	Text        string
+	Drifted     int
+	Comparisons []*databaseDiff
+	// Top lists the first differences, across every database
+	Top []string
+	// More is the number of differences not listed in Top
+	More int
+}
+
+func (c *ChatOutput) summarize(res *diffResult) chatSummary {
+	cs := chatSummary{Comparisons: res.Comparisons}
+
+	total := 0
+	for _, dd := range res.Comparisons {
+		if len(dd.Differences) > 0 {
+			cs.Drifted++
+		}
+		for _, od := range dd.Differences {
+			total++
+			if len(cs.Top) < c.top {
+				cs.Top = append(cs.Top, fmt.Sprintf("%s: %s", dd.Target, od))
+			}
+		}
+	}
+	cs.More = total - len(cs.Top)
+
+	if cs.Drifted == 0 {
+		cs.Text = fmt.Sprintf("No schema drift from %s", res.Baseline)
+	} else {
+		cs.Text = fmt.Sprintf("Schema drift detected: %d of %d database(s) differ from %s", cs.Drifted, len(res.Comparisons), res.Baseline)
+	}
+
+	return cs
+}
+
+// chatList returns the top differences as a bulleted list
+func chatList(cs chatSummary) string {
+	lines := make([]string, 0, len(cs.Top)+1)
+	for _, t := range cs.Top {
+		lines = append(lines, "• "+chatEscape(t))
+	}
+	if cs.More > 0 {
+		lines = append(lines, fmt.Sprintf("_…and %d more_", cs.More))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// chatTruncate limits text to limit characters, replacing the end of longer text with an ellipsis.  An escaped
+// character is never cut in half.
+func chatTruncate(v string, limit int) string {
+	r := []rune(v)
+	if len(r) <= limit {
+		return v
+	}
+
+	cut := string(r[:limit-1])
+	if i := strings.LastIndexByte(cut, '&'); i >= 0 && !strings.Contains(cut[i:], ";") {
+		cut = cut[:i]
+	}
+	return cut + "…"
+}
+
+// blocksPayload builds a payload using Block Kit blocks, with the text as the notification fallback.  Text is truncated
+// to the lengths Slack accepts for header and section blocks.
+func (c *ChatOutput) blocksPayload(cs chatSummary) map[string]any {
+	text := func(t string) map[string]any { return map[string]any{"type": "mrkdwn", "text": t} }
+	section := func(t string) map[string]any {
+		return map[string]any{"type": "section", "text": text(chatTruncate(t, chatSectionLimit))}
+	}
+
+	blocks := []any{
+		map[string]any{"type": "header", "text": map[string]any{"type": "plain_text", "text": chatTruncate(c.title, chatHeaderLimit)}},
+		section(chatEscape(cs.Text)),
+	}
+
+	counts := make([]string, 0, len(cs.Comparisons))
+	for _, dd := range cs.Comparisons {
+		counts = append(counts, fmt.Sprintf("*%s*: %s", chatEscape(dd.Target.String()), chatCounts(dd)))
+	}
+	if len(counts) > 0 {
+		blocks = append(blocks, section(strings.Join(counts, "\n")))
+	}
+
+	if list := chatList(cs); list != "" {
+		blocks = append(blocks, section(list))
+	}
+
+	if c.reportURL != "" {
+		blocks = append(blocks, map[string]any{"type": "context", "elements": []any{text(fmt.Sprintf("<%s|View full report>", c.reportURL))}})
+	}
+
+	return map[string]any{"text": cs.Text, "blocks": blocks}
+}
+
+// attachmentsPayload builds a payload using message attachments, as rendered by Mattermost and older Slack clients
+func (c *ChatOutput) attachmentsPayload(cs chatSummary) map[string]any {
+	color := "good"
+	if cs.Drifted > 0 {
+		color = "danger"
+	}
+
+	fields := make([]any, 0, len(cs.Comparisons))
+	for _, dd := range cs.Comparisons {
+		fields = append(fields, map[string]any{"title": dd.Target.String(), "value": chatCounts(dd), "short": true})
+	}
+
+	att := map[string]any{
+		"fallback": cs.Text,
+		"color":    color,
+		"title":    c.title,
+		"pretext":  chatEscape(cs.Text),
+		"text":     chatList(cs),
+		"fields":   fields,
+	}
+	if c.reportURL != "" {
+		att["title_link"] = c.reportURL
+	}
+
+	return map[string]any{"attachments": []any{att}}
+}
+
+// payload returns the body of the webhook request
+func (c *ChatOutput) payload(cs chatSummary) ([]byte, error) {
+	var p map[string]any
+	if c.style == chatStyleAttachments {
+		p = c.attachmentsPayload(cs)
+	} else {
+		p = c.blocksPayload(cs)
+	}
+
+	for k, v := range map[string]string{"channel": c.channel, "username": c.username, "icon_emoji": c.iconEmoji} {
+		if v != "" {
+			p[k] = v
+		}
+	}
+
+	return json.Marshal(p)
+}
+
+// chatWriter discards the rendered result, posting a notification built from the summaries once rendering has
+// completed
+type chatWriter struct {
+	out       *ChatOutput
+	summaries connectionSummaries
+}
+
+func (w *chatWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (w *chatWriter) Publish() error {
+	cs := w.out.summarize(buildDiff(w.summaries))
+	if cs.Drifted == 0 && !w.out.always {
+		return nil
+	}
+
+	body, err := w.out.payload(cs)
+	if err != nil {
+		return fmt.Errorf("error encoding notification: %w", err)
+	}
+
+	return w.out.http.deliver(body)
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/urfave/cli/v2"
+)
+
+func newTestChatOutput(t *testing.T, cfg map[string]string) *ChatOutput {
+	t.Helper()
+
+	out, err := newChatOutput(cli.NewContext(nil, nil, nil), cfg)
+	if err != nil {
+		t.Fatalf("error building chat output: %v", err)
+	}
+	return out.(*ChatOutput)
+}
+
+// notify renders the summaries into the output, returning the requests received by the webhook
+func notify(t *testing.T, cfg map[string]string, summaries connectionSummaries) []httpRequest {
+	t.Helper()
+
+	rec := &httpRecorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	cfg["url"] = srv.URL + "/hooks/chat"
+	out := newTestChatOutput(t, cfg)
+
+	if err := renderDatabases(out, summaries, true, renderRefs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return rec.Requests()
+}
+
+func TestChatOutputGolden(t *testing.T) {
+	tests := []struct {
+		style  string
+		golden string
+	}{
+		{chatStyleBlocks, "chat-blocks.json"},
+		{chatStyleAttachments, "chat-attachments.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.style, func(t *testing.T) {
+			reqs := notify(t, map[string]string{
+				"style":      tt.style,
+				"title":      "Nightly <drift> check",
+				"top":        "3",
+				"report-url": "https://ci.example.com/report.html",
+				"channel":    "#dba",
+				"username":   "schema-bot",
+				"icon-emoji": ":warning:",
+			}, fixtureSummaries(t))
+
+			if len(reqs) != 1 {
+				t.Fatalf("expected 1 request, saw %d", len(reqs))
+			}
+			if ct := reqs[0].Headers.Get("Content-Type"); ct != "application/json" {
+				t.Errorf("expected a JSON body, saw content type %q", ct)
+			}
+
+			var b bytes.Buffer
+			if err := json.Indent(&b, []byte(reqs[0].Body), "", "  "); err != nil {
+				t.Fatalf("invalid JSON body: %v\n%s", err, reqs[0].Body)
+			}
+			b.WriteByte('\n')
+			assertGolden(t, tt.golden, b.Bytes())
+		})
+	}
+}
+
+func TestChatOutputNoDrift(t *testing.T) {
+	summaries := connectionSummaries{ddlSummary(t, "base", "base.sql"), ddlSummary(t, "copy", "base.sql")}
+
+	if reqs := notify(t, map[string]string{}, summaries); len(reqs) != 0 {
+		t.Errorf("expected nothing to be posted without drift, saw %d request(s)", len(reqs))
+	}
+	if reqs := notify(t, map[string]string{"always": "false"}, summaries); len(reqs) != 0 {
+		t.Errorf("expected nothing to be posted with always=false, saw %d request(s)", len(reqs))
+	}
+
+	reqs := notify(t, map[string]string{"always": "true"}, summaries)
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 request with always=true, saw %d", len(reqs))
+	}
+	if want := "No schema drift from `base`.`shop`"; !strings.Contains(reqs[0].Body, want) {
+		t.Errorf("expected the body to contain %q:\n%s", want, reqs[0].Body)
+	}
+}
+
+func TestChatTruncate(t *testing.T) {
+	tests := []struct {
+		v     string
+		limit int
+		want  string
+	}{
+		{"short", 10, "short"},
+		{"exactly10!", 10, "exactly10!"},
+		{"longer than ten", 10, "longer th…"},
+		{"ééééééééééé", 5, "éééé…"},
+		{"a &amp; b", 6, "a …"},
+		{"a &amp; b", 8, "a &amp;…"},
+	}
+
+	for _, tt := range tests {
+		if got := chatTruncate(tt.v, tt.limit); got != tt.want {
+			t.Errorf("chatTruncate(%q, %d): got %q, want %q", tt.v, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestChatOutputBlockLimits(t *testing.T) {
+	co := newTestChatOutput(t, map[string]string{"url": "https://chat.example.com/hooks/abc", "title": strings.Repeat("t", 200)})
+
+	cs := chatSummary{Text: strings.Repeat("drift ", 1000)}
+	for i := range 100 {
+		cs.Top = append(cs.Top, fmt.Sprintf("`target`.`db%d`: column t.c type changed from %q to %q & more", i, "int", "bigint"))
+	}
+
+	payload := co.blocksPayload(cs)
+	if payload["text"] != cs.Text {
+		t.Error("expected the fallback text not to be truncated")
+	}
+
+	blocks := payload["blocks"].([]any)
+	if len(blocks) != 3 {
+		t.Fatalf("expected 3 blocks, saw %d", len(blocks))
+	}
+	for i, block := range blocks {
+		b := block.(map[string]any)
+		text := b["text"].(map[string]any)["text"].(string)
+
+		limit := chatSectionLimit
+		if b["type"] == "header" {
+			limit = chatHeaderLimit
+		}
+		if n := utf8.RuneCountInString(text); n > limit {
+			t.Errorf("block %d: %s text has %d characters, more than %d", i, b["type"], n, limit)
+		}
+		if !strings.HasSuffix(text, "…") {
+			t.Errorf("block %d: expected truncated text to end with an ellipsis", i)
+		}
+	}
+}
+
+func TestChatOutputConfig(t *testing.T) {
+	tests := map[string]map[string]string{
+		"missing url":    {},
+		"invalid url":    {"url": "hooks.example.com"},
+		"unknown style":  {"url": "https://chat.example.com", "style": "cards"},
+		"invalid top":    {"url": "https://chat.example.com", "top": "few"},
+		"negative top":   {"url": "https://chat.example.com", "top": "-1"},
+		"invalid always": {"url": "https://chat.example.com", "always": "often"},
+	}
+
+	for name, cfg := range tests {
+		if _, err := newChatOutput(cli.NewContext(nil, nil, nil), cfg); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -1,28 +1,84 @@
 package main
 
 import (
+	"compress/gzip"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand/v2"
 	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"strconv"
+	"strings"
+	"text/template"
+	"time"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/urfave/cli/v2"
 )
 
 const OutputFile = "file"
 
-var _ Output = (*FileOutput)(nil)
+const (
+	compressGzip = "gzip"
+	compressZstd = "zstd"
+)
+
+var (
+	_ Output           = (*FileOutput)(nil)
+	_ formatAware      = (*FileOutput)(nil)
+	_ databaseSplitter = (*FileOutput)(nil)
+	_ Publisher        = (*fileWriter)(nil)
+)
+
+// fileDateSentinel and fileTimeSentinel stand in for the date and time of a report when building the patterns matching
+// every report
+const (
+	fileDateSentinel = "\x00"
+	fileTimeSentinel = "\x01"
+)
+
+// unsafePathChars matches characters replaced in labels and database names before they are used in paths
+var unsafePathChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
 
 type FileOutput struct {
 	dest  string
 	trunc bool
 	mode  os.FileMode
+
+	// tmpl is set if dest contains placeholders
+	tmpl   *template.Template
+	split  bool
+	keep   int
+	now    time.Time
+	format string
+	ref    databaseRef
+
+	compress string
+	atomic   bool
+}
+
+// fileDestData is the value dest templates are executed against
+type fileDestData struct {
+	// Date is the date of the run, as YYYY-MM-DD
+	Date string
+	// Time is the time of the run, as HHMMSS
+	Time string
+	// Label is the label of the connection the database was read from
+	Label string
+	// Database is the name of the database
+	Database string
+	// Format is the name of the format rendered
+	Format string
 }
 
 func newFileOutput(_ *cli.Context, cfg map[string]string) (Output, error) {
 	fo := FileOutput{
 		mode:  os.FileMode(0666),
 		trunc: true,
+		now:   time.Now(),
 	}
 
 	if dest, ok := cfg["dest"]; !ok {
@@ -47,6 +103,71 @@ func newFileOutput(_ *cli.Context, cfg map[string]string) (Output, error) {
 		}
 	}
 
+	if split, ok := cfg["split"]; ok {
+		if b, err := strconv.ParseBool(split); err != nil {
+			return nil, fmt.Errorf("unable to parse \"split\" value %q as bool: %w", split, err)
+		} else {
+			fo.split = b
+		}
+	}
+
+	if keep, ok := cfg["keep"]; ok {
+		if i, err := strconv.Atoi(keep); err != nil {
+			return nil, fmt.Errorf("unable to parse \"keep\" value %q as int: %w", keep, err)
+		} else if i < 1 {
+			return nil, fmt.Errorf("\"keep\" must be at least 1, saw %d", i)
+		} else {
+			fo.keep = i
+		}
+	}
+
+	if compress, ok := cfg["compress"]; ok {
+		switch compress {
+		case compressGzip, compressZstd:
+			fo.compress = compress
+		default:
+			return nil, fmt.Errorf("unknown \"compress\" value %q specified, expected one of %v", compress, []string{compressGzip, compressZstd})
+		}
+	}
+
+	if atomic, ok := cfg["atomic"]; ok {
+		if b, err := strconv.ParseBool(atomic); err != nil {
+			return nil, fmt.Errorf("unable to parse \"atomic\" value %q as bool: %w", atomic, err)
+		} else {
+			fo.atomic = b
+		}
+	}
+
+	if fo.atomic && !fo.trunc {
+		return nil, errors.New("\"atomic\" cannot be combined with \"trunc=false\"")
+	}
+
+	if strings.Contains(fo.dest, "{{") {
+		tmpl, err := template.New("dest").Option("missingkey=error").Parse(fo.dest)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse \"dest\" value %q as template: %w", fo.dest, err)
+		}
+		fo.tmpl = tmpl
+
+		// executing the template once reports unknown fields before anything is rendered
+		if _, err = fo.pattern(); err != nil {
+			return nil, fmt.Errorf("unable to parse \"dest\" value %q as template: %w", fo.dest, err)
+		}
+	}
+
+	if fo.split && fo.tmpl == nil {
+		return nil, errors.New("\"split\" requires \"dest\" to contain a placeholder such as {{.Database}}")
+	}
+
+	if fo.keep > 0 {
+		if fo.tmpl == nil {
+			return nil, errors.New("\"keep\" requires \"dest\" to contain {{.Date}} or {{.Time}}")
+		}
+		if p, err := fo.pattern(); err != nil || !strings.Contains(p, "*") {
+			return nil, errors.New("\"keep\" requires \"dest\" to contain {{.Date}} or {{.Time}}")
+		}
+	}
+
 	return &fo, nil
 }
 
@@ -54,6 +175,175 @@ func (*FileOutput) Type() string {
 	return OutputFile
 }
 
+// setFormat sets the format named by {{.Format}}
+func (f *FileOutput) setFormat(format string) {
+	f.format = format
+}
+
+func (f *FileOutput) splitting() bool {
+	return f.split
+}
+
+// setDatabase sets the database named by {{.Label}} and {{.Database}}
+func (f *FileOutput) setDatabase(ref databaseRef) {
+	f.ref = ref
+}
+
+// destData returns the values of the placeholders of dest, with the date and time set to the provided value
+func (f *FileOutput) destData(date, tm string) fileDestData {
+	return fileDestData{
+		Date:     date,
+		Time:     tm,
+		Label:    unsafePathChars.ReplaceAllString(f.ref.Connection, "_"),
+		Database: unsafePathChars.ReplaceAllString(f.ref.Database, "_"),
+		Format:   f.format,
+	}
+}
+
+// path returns the path of the file to write
+func (f *FileOutput) path() (string, error) {
+	if f.tmpl == nil {
+		return f.dest, nil
+	}
+
+	var b strings.Builder
+	if err := f.tmpl.Execute(&b, f.destData(f.now.Format(time.DateOnly), f.now.Format("150405"))); err != nil {
+		return "", fmt.Errorf("error executing \"dest\" template: %w", err)
+	}
+	return b.String(), nil
+}
+
+// sentinelPath returns the path of the file to write, with the date and time replaced by sentinels
+func (f *FileOutput) sentinelPath() (string, error) {
+	var b strings.Builder
+	if err := f.tmpl.Execute(&b, f.destData(fileDateSentinel, fileTimeSentinel)); err != nil {
+		return "", fmt.Errorf("error executing \"dest\" template: %w", err)
+	}
+	return b.String(), nil
+}
+
+// pattern returns a glob matching the path of the file written by every run, differing only by date and time
+func (f *FileOutput) pattern() (string, error) {
+	p, err := f.sentinelPath()
+	if err != nil {
+		return "", err
+	}
+
+	escaped := strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`).Replace(p)
+	return strings.NewReplacer(fileDateSentinel, "*", fileTimeSentinel, "*").Replace(escaped), nil
+}
+
+// reportMatcher returns a function reporting whether a path matched by pattern was written by a run, i.e. whether
+// every date is a YYYY-MM-DD date and every time a HHMMSS time.  Other files matching the glob, such as
+// "drift-latest.json" for "drift-{{.Date}}.json", are not reports.
+func (f *FileOutput) reportMatcher() (func(string) bool, error) {
+	p, err := f.sentinelPath()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		b       strings.Builder
+		layouts []string
+	)
+	b.WriteString("^")
+	for _, c := range regexp.QuoteMeta(filepath.Clean(p)) {
+		switch string(c) {
+		case fileDateSentinel:
+			b.WriteString(`(\d{4}-\d{2}-\d{2})`)
+			layouts = append(layouts, time.DateOnly)
+		case fileTimeSentinel:
+			b.WriteString(`(\d{6})`)
+			layouts = append(layouts, "150405")
+		default:
+			b.WriteRune(c)
+		}
+	}
+	b.WriteString("$")
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("error compiling pattern for \"dest\": %w", err)
+	}
+
+	return func(path string) bool {
+		m := re.FindStringSubmatch(filepath.Clean(path))
+		if m == nil {
+			return false
+		}
+		for i, layout := range layouts {
+			if _, err := time.Parse(layout, m[i+1]); err != nil {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// prune removes the oldest files written by previous runs, retaining the newest keep files
+func (f *FileOutput) prune() error {
+	pattern, err := f.pattern()
+	if err != nil {
+		return err
+	}
+	isReport, err := f.reportMatcher()
+	if err != nil {
+		return err
+	}
+
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return fmt.Errorf("error listing files matching %q: %w", pattern, err)
+	}
+
+	type report struct {
+		path    string
+		modTime time.Time
+	}
+
+	reports := make([]report, 0, len(matches))
+	for _, m := range matches {
+		if !isReport(m) {
+			continue
+		}
+		fi, err := os.Stat(m)
+		if err != nil {
+			return fmt.Errorf("error reading file %q: %w", m, err)
+		}
+		if fi.Mode().IsRegular() {
+			reports = append(reports, report{path: m, modTime: fi.ModTime()})
+		}
+	}
+
+	// newest first, with names breaking ties
+	slices.SortFunc(reports, func(a, b report) int {
+		if c := b.modTime.Compare(a.modTime); c != 0 {
+			return c
+		}
+		return strings.Compare(b.path, a.path)
+	})
+
+	for i := f.keep; i < len(reports); i++ {
+		if err := os.Remove(reports[i].path); err != nil {
+			return fmt.Errorf("error removing file %q: %w", reports[i].path, err)
+		}
+	}
+
+	return nil
+}
+
+// createTemp creates a file to write to in place of dest, in the same directory so that it can be renamed over dest
+func createTemp(dest string, mode os.FileMode) (*os.File, error) {
+	for i := 0; ; i++ {
+		name := filepath.Join(filepath.Dir(dest), fmt.Sprintf(".%s.%d.tmp", filepath.Base(dest), rand.Uint32()))
+		f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+		if errors.Is(err, os.ErrExist) && i < 100 {
+			continue
+		}
+		return f, err
+	}
+}
+
 func (f *FileOutput) Writer() (io.Writer, error) {
 	flgs := os.O_RDWR | os.O_CREATE
 
@@ -61,9 +351,104 @@ func (f *FileOutput) Writer() (io.Writer, error) {
 		flgs |= os.O_TRUNC
 	}
 
-	w, err := os.OpenFile(f.dest, flgs, f.mode)
+	dest, err := f.path()
 	if err != nil {
-		return nil, fmt.Errorf("error opening file %q: %w", f.dest, err)
+		return nil, err
+	}
+
+	// placeholders may name directories which do not exist yet
+	if f.tmpl != nil {
+		if err = os.MkdirAll(filepath.Dir(dest), 0777); err != nil {
+			return nil, fmt.Errorf("error creating directory %q: %w", filepath.Dir(dest), err)
+		}
+	}
+
+	fw := fileWriter{out: f, dest: dest}
+
+	if f.atomic {
+		if fw.file, err = createTemp(dest, f.mode); err != nil {
+			return nil, fmt.Errorf("error creating temporary file for %q: %w", dest, err)
+		}
+		fw.tmp = fw.file.Name()
+	} else if fw.file, err = os.OpenFile(dest, flgs, f.mode); err != nil {
+		return nil, fmt.Errorf("error opening file %q: %w", dest, err)
+	}
+
+	switch f.compress {
+	case compressGzip:
+		fw.enc = gzip.NewWriter(fw.file)
+	case compressZstd:
+		if fw.enc, err = zstd.NewWriter(fw.file); err != nil {
+			_ = fw.Close()
+			return nil, fmt.Errorf("error creating zstd encoder: %w", err)
+		}
+	}
+
+	return &fw, nil
+}
+
+// fileWriter writes to the destination file, or to a temporary file renamed over it once the result has been rendered
+// in full.  Files written by previous runs are pruned once the result is in place.
+type fileWriter struct {
+	out  *FileOutput
+	dest string
+	file *os.File
+	// enc compresses writes to file, and is nil if not compressing
+	enc io.WriteCloser
+	// tmp is the path of the temporary file, and is empty if not writing atomically
+	tmp string
+
+	finished  bool
+	published bool
+}
+
+func (w *fileWriter) Write(p []byte) (int, error) {
+	if w.enc != nil {
+		return w.enc.Write(p)
+	}
+	return w.file.Write(p)
+}
+
+// finish flushes the encoder and closes the file
+func (w *fileWriter) finish() error {
+	if w.finished {
+		return nil
+	}
+	w.finished = true
+
+	var err error
+	if w.enc != nil {
+		err = w.enc.Close()
+	}
+	if w.tmp != "" && err == nil {
+		err = w.file.Sync()
+	}
+	return errors.Join(err, w.file.Close())
+}
+
+func (w *fileWriter) Publish() error {
+	if err := w.finish(); err != nil {
+		return fmt.Errorf("error closing file %q: %w", w.file.Name(), err)
+	}
+
+	if w.tmp != "" {
+		if err := os.Rename(w.tmp, w.dest); err != nil {
+			return fmt.Errorf("error renaming %q to %q: %w", w.tmp, w.dest, err)
+		}
+	}
+	w.published = true
+
+	if w.out.keep == 0 {
+		return nil
+	}
+	return w.out.prune()
+}
+
+// Close closes the file if the result was not published, removing it if it was written atomically
+func (w *fileWriter) Close() error {
+	err := w.finish()
+	if w.tmp != "" && !w.published {
+		err = errors.Join(err, os.Remove(w.tmp))
 	}
-	return w, nil
+	return err
 }
@@ -0,0 +1,364 @@
+package main
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// testRunTime is the time of the run used by file output tests
+var testRunTime = time.Date(2024, 3, 9, 14, 5, 6, 0, time.UTC)
+
+func newTestFileOutput(t *testing.T, cfg map[string]string) *FileOutput {
+	t.Helper()
+
+	out, err := newFileOutput(nil, cfg)
+	if err != nil {
+		t.Fatalf("error building file output: %v", err)
+	}
+	fo := out.(*FileOutput)
+	fo.now = testRunTime
+	return fo
+}
+
+// listFiles returns the sorted paths of every file within dir, relative to it
+func listFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	out := make([]string, 0)
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		out = append(out, filepath.ToSlash(rel))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	slices.Sort(out)
+	return out
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestFileOutputPath(t *testing.T) {
+	tests := []struct {
+		dest string
+		ref  databaseRef
+		want string
+	}{
+		{"drift.json", databaseRef{"prod", "shop"}, "drift.json"},
+		{"{{.Date}}/{{.Time}}.{{.Format}}", databaseRef{"prod", "shop"}, "2024-03-09/140506.json"},
+		{"{{.Label}}-{{.Database}}.txt", databaseRef{"prod/eu 1", "my`db"}, "prod_eu_1-my_db.txt"},
+		{"{{.Database}}-{{.Date}}-{{.Date}}.txt", databaseRef{"prod", "shop.v2"}, "shop.v2-2024-03-09-2024-03-09.txt"},
+	}
+
+	for _, tt := range tests {
+		fo := newTestFileOutput(t, map[string]string{"dest": tt.dest})
+		fo.setFormat(FormatJSON)
+		fo.setDatabase(tt.ref)
+
+		got, err := fo.path()
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.dest, err)
+		} else if got != tt.want {
+			t.Errorf("%q: got %q, want %q", tt.dest, got, tt.want)
+		}
+	}
+}
+
+func TestFileOutputConfig(t *testing.T) {
+	tests := map[string]map[string]string{
+		"missing dest":           {},
+		"invalid trunc":          {"dest": "out", "trunc": "sometimes"},
+		"invalid mode":           {"dest": "out", "mode": "rw"},
+		"invalid split":          {"dest": "{{.Database}}", "split": "both"},
+		"invalid keep":           {"dest": "{{.Date}}", "keep": "all"},
+		"zero keep":              {"dest": "{{.Date}}", "keep": "0"},
+		"unknown compress":       {"dest": "out", "compress": "lz4"},
+		"invalid atomic":         {"dest": "out", "atomic": "mostly"},
+		"atomic without trunc":   {"dest": "out", "atomic": "true", "trunc": "false"},
+		"unparsable template":    {"dest": "{{.Date"},
+		"unknown placeholder":    {"dest": "{{.Host}}.json"},
+		"split without template": {"dest": "out", "split": "true"},
+		"keep without template":  {"dest": "out", "keep": "3"},
+		"keep without date":      {"dest": "{{.Database}}.json", "keep": "3"},
+	}
+
+	for name, cfg := range tests {
+		if _, err := newFileOutput(nil, cfg); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+// renderRefs writes the references of every database rendered
+func renderRefs(summaries connectionSummaries, w io.Writer) error {
+	for _, db := range summaries.AllDatabases() {
+		if _, err := fmt.Fprintln(w, db.Ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestFileOutputSplit(t *testing.T) {
+	summaries := fixtureSummaries(t)
+
+	tests := []struct {
+		name         string
+		split        string
+		withBaseline bool
+		want         map[string]string
+	}{
+		{
+			name:  "single file names the first database",
+			split: "false",
+			want: map[string]string{
+				"base-shop.txt": "`base`.`shop`\n`target`.`shop`\n",
+			},
+		},
+		{
+			name:  "file per database",
+			split: "true",
+			want: map[string]string{
+				"base-shop.txt":   "`base`.`shop`\n",
+				"target-shop.txt": "`target`.`shop`\n",
+			},
+		},
+		{
+			name:         "file per comparison",
+			split:        "true",
+			withBaseline: true,
+			want: map[string]string{
+				"target-shop.txt": "`base`.`shop`\n`target`.`shop`\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			fo := newTestFileOutput(t, map[string]string{
+				"dest":  filepath.Join(dir, "{{.Label}}-{{.Database}}.txt"),
+				"split": tt.split,
+			})
+
+			if err := renderDatabases(fo, summaries, tt.withBaseline, renderRefs); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := listFiles(t, dir)
+			want := make([]string, 0, len(tt.want))
+			for name, content := range tt.want {
+				want = append(want, name)
+				if c := readFile(t, filepath.Join(dir, name)); c != content {
+					t.Errorf("%s: got %q, want %q", name, c, content)
+				}
+			}
+			slices.Sort(want)
+			if !slices.Equal(got, want) {
+				t.Errorf("got files %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestFileOutputReportMatcher(t *testing.T) {
+	fo := newTestFileOutput(t, map[string]string{"dest": "reports/{{.Database}}/drift-{{.Date}}T{{.Time}}.json"})
+	fo.setDatabase(databaseRef{"prod", "shop"})
+
+	isReport, err := fo.reportMatcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]bool{
+		"reports/shop/drift-2024-03-09T140506.json":   true,
+		"./reports/shop/drift-2023-12-31T235959.json": true,
+		"reports/shop/drift-latest.json":              false,
+		"reports/shop/drift-2024-13-01T000000.json":   false,
+		"reports/shop/drift-2024-03-09T250000.json":   false,
+		"reports/shop/drift-2024-03-09T14050.json":    false,
+		"reports/shop/drift-2024-03-09T140506.json.1": false,
+		"reports/other/drift-2024-03-09T140506.json":  false,
+	}
+
+	for path, want := range tests {
+		if got := isReport(path); got != want {
+			t.Errorf("%q: got %t, want %t", path, got, want)
+		}
+	}
+}
+
+func TestFileOutputRetention(t *testing.T) {
+	dir := t.TempDir()
+
+	// previous runs, oldest first, and files which only resemble them
+	previous := []string{
+		"shop/drift-2024-03-05.json",
+		"shop/drift-2024-03-06.json",
+		"shop/drift-2024-03-07.json",
+		"shop/drift-2024-03-08.json",
+		"audit/drift-2024-03-01.json",
+	}
+	unrelated := []string{
+		"shop/drift-latest.json",
+		"shop/drift-2024-02-30.json",
+		"shop/drift-2024-03-01.json.bak",
+	}
+
+	for i, name := range append(slices.Clone(previous), unrelated...) {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0666); err != nil {
+			t.Fatal(err)
+		}
+		mtime := testRunTime.Add(-time.Duration(len(previous)+len(unrelated)-i) * time.Hour)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fo := newTestFileOutput(t, map[string]string{
+		"dest": filepath.Join(dir, "{{.Database}}/drift-{{.Date}}.json"),
+		"keep": "2",
+	})
+	fo.setDatabase(databaseRef{"prod", "shop"})
+
+	if err := publish(t, fo, "current"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{
+		"audit/drift-2024-03-01.json",
+		"shop/drift-2024-02-30.json",
+		"shop/drift-2024-03-01.json.bak",
+		"shop/drift-2024-03-08.json",
+		"shop/drift-2024-03-09.json",
+		"shop/drift-latest.json",
+	}
+	if got := listFiles(t, dir); !slices.Equal(got, want) {
+		t.Errorf("got files %v, want %v", got, want)
+	}
+	if got := readFile(t, filepath.Join(dir, "shop", "drift-2024-03-09.json")); got != "current" {
+		t.Errorf("expected the current run to be written, saw %q", got)
+	}
+}
+
+func TestFileOutputCompress(t *testing.T) {
+	const body = "`base`.`shop`\n`target`.`shop`\n"
+
+	tests := []struct {
+		compress string
+		magic    []byte
+		decode   func(io.Reader) (io.Reader, error)
+	}{
+		{compressGzip, []byte{0x1f, 0x8b}, func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }},
+		{compressZstd, []byte{0x28, 0xb5, 0x2f, 0xfd}, func(r io.Reader) (io.Reader, error) { return zstd.NewReader(r) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.compress, func(t *testing.T) {
+			for _, atomic := range []string{"false", "true"} {
+				path := filepath.Join(t.TempDir(), "drift.txt."+tt.compress)
+				fo := newTestFileOutput(t, map[string]string{"dest": path, "compress": tt.compress, "atomic": atomic})
+
+				if err := renderDatabases(fo, fixtureSummaries(t), false, renderRefs); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				raw := readFile(t, path)
+				if !strings.HasPrefix(raw, string(tt.magic)) {
+					t.Errorf("atomic=%s: expected %s magic bytes, saw %q", atomic, tt.compress, raw[:min(len(raw), 4)])
+				}
+
+				r, err := tt.decode(strings.NewReader(raw))
+				if err != nil {
+					t.Fatalf("atomic=%s: error opening decoder: %v", atomic, err)
+				}
+				b, err := io.ReadAll(r)
+				if err != nil {
+					t.Fatalf("atomic=%s: error decoding: %v", atomic, err)
+				}
+				if string(b) != body {
+					t.Errorf("atomic=%s: got %q, want %q", atomic, b, body)
+				}
+			}
+		})
+	}
+}
+
+func TestFileOutputAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "drift.txt")
+	if err := os.WriteFile(path, []byte("previous"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	fo := newTestFileOutput(t, map[string]string{"dest": path, "atomic": "true", "mode": "600"})
+
+	// a failed render leaves the previous file in place, and no temporary file behind
+	errRender := errors.New("render failed")
+	err := renderDatabases(fo, fixtureSummaries(t), false, func(summaries connectionSummaries, w io.Writer) error {
+		if err := renderRefs(summaries, w); err != nil {
+			return err
+		}
+		// the partial result must not be visible at dest while rendering
+		if got := readFile(t, path); got != "previous" {
+			t.Errorf("expected dest to be unchanged while rendering, saw %q", got)
+		}
+		return errRender
+	})
+	if !errors.Is(err, errRender) {
+		t.Fatalf("expected the render error, saw %v", err)
+	}
+	if got := readFile(t, path); got != "previous" {
+		t.Errorf("expected the previous file to be kept, saw %q", got)
+	}
+	if got := listFiles(t, dir); !slices.Equal(got, []string{"drift.txt"}) {
+		t.Errorf("expected the temporary file to be removed, saw %v", got)
+	}
+
+	// a successful render replaces it
+	if err = renderDatabases(fo, fixtureSummaries(t), false, renderRefs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := readFile(t, path), "`base`.`shop`\n`target`.`shop`\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := listFiles(t, dir); !slices.Equal(got, []string{"drift.txt"}) {
+		t.Errorf("expected only dest to remain, saw %v", got)
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && fi.Mode().Perm() != 0600 {
+		t.Errorf("expected mode 0600, saw %v", fi.Mode().Perm())
+	}
+}
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+const OutputGit = "git"
+
+// gitMaxChanges limits the number of changes listed in a commit message
+const gitMaxChanges = 50
+
+var (
+	_ Output    = (*GitOutput)(nil)
+	_ Publisher = (*gitWriter)(nil)
+)
+
+// GitOutput writes the rendered result to a file within a git working tree and commits it, optionally pushing the
+// commit.  When the file is a summary snapshot the commit message lists the changes from the previously committed
+// snapshot.
+type GitOutput struct {
+	ctx     context.Context
+	repo    string
+	path    string
+	message string
+	push    bool
+	remote  string
+	branch  string
+	env     []string
+}
+
+func newGitOutput(cctx *cli.Context, cfg map[string]string) (Output, error) {
+	gto := GitOutput{
+		ctx:     cctx.Context,
+		message: "Update schema snapshot",
+		remote:  "origin",
+	}
+
+	if v, ok := cfg["repo"]; !ok {
+		return nil, fmt.Errorf("output %q requires config key %q to be set", OutputGit, "repo")
+	} else {
+		gto.repo = v
+	}
+
+	if v, ok := cfg["path"]; !ok {
+		return nil, fmt.Errorf("output %q requires config key %q to be set", OutputGit, "path")
+	} else if !filepath.IsLocal(v) {
+		return nil, fmt.Errorf("\"path\" value %q must be relative to and within \"repo\"", v)
+	} else {
+		gto.path = v
+	}
+
+	if v, ok := cfg["message"]; ok && v != "" {
+		gto.message = v
+	}
+
+	if v, ok := cfg["push"]; ok {
+		if b, err := strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("unable to parse \"push\" value %q as bool: %w", v, err)
+		} else {
+			gto.push = b
+		}
+	}
+
+	if v, ok := cfg["remote"]; ok {
+		gto.remote = v
+	}
+
+	if v, ok := cfg["branch"]; ok {
+		gto.branch = v
+	}
+
+	// the author is also used as committer, so that commits can be made where git has no identity configured
+	if v, ok := cfg["author"]; ok {
+		addr, err := mail.ParseAddress(v)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse \"author\" value %q as \"Name <email>\": %w", v, err)
+		}
+		gto.env = []string{
+			"GIT_AUTHOR_NAME=" + addr.Name,
+			"GIT_AUTHOR_EMAIL=" + addr.Address,
+			"GIT_COMMITTER_NAME=" + addr.Name,
+			"GIT_COMMITTER_EMAIL=" + addr.Address,
+		}
+	}
+
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("output %q requires git to be installed: %w", OutputGit, err)
+	}
+
+	if _, err := gto.git("rev-parse", "--is-inside-work-tree"); err != nil {
+		return nil, fmt.Errorf("\"repo\" value %q is not a git working tree: %w", gto.repo, err)
+	}
+
+	return &gto, nil
+}
+
+func (*GitOutput) Type() string {
+	return OutputGit
+}
+
+func (g *GitOutput) Writer() (io.Writer, error) {
+	return &gitWriter{out: g}, nil
+}
+
+// git runs a git command within the repository, returning its output
+func (g *GitOutput) git(args ...string) (string, error) {
+	cmd := exec.CommandContext(g.ctx, "git", append([]string{"-C", g.repo}, args...)...)
+	cmd.Env = append(os.Environ(), g.env...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("error running git %s: %w: %s", args[0], err, msg)
+		}
+		return "", fmt.Errorf("error running git %s: %w", args[0], err)
+	}
+	return string(out), nil
+}
+
+// changes lists the differences between the previously committed snapshot and the new one, returning nil if either
+// is not a summary snapshot, and an empty list if their schemas are identical
+func (g *GitOutput) changes(body []byte) []string {
+	prev, err := g.git("show", "HEAD:./"+filepath.ToSlash(g.path))
+	if err != nil {
+		return nil
+	}
+
+	decode := func(b []byte) *snapshotFile {
+		js, err := snapshotJSON(g.path, b)
+		if err != nil {
+			return nil
+		}
+		snap, err := decodeSnapshot(js)
+		if err != nil {
+			return nil
+		}
+		return snap
+	}
+
+	before, after := decode([]byte(prev)), decode(body)
+	if before == nil || after == nil {
+		return nil
+	}
+
+	beforeDBs := make(map[databaseRef]*databaseSummary)
+	for _, db := range before.Connections.AllDatabases() {
+		beforeDBs[db.Ref] = db.Summary
+	}
+
+	out := make([]string, 0)
+	for _, db := range after.Connections.AllDatabases() {
+		old, ok := beforeDBs[db.Ref]
+		if !ok {
+			out = append(out, fmt.Sprintf("%s: database was added", db.Ref))
+			continue
+		}
+		delete(beforeDBs, db.Ref)
+
+		for _, od := range compareDatabases(old, db.Summary) {
+			out = append(out, fmt.Sprintf("%s: %s", db.Ref, od))
+		}
+	}
+	for _, db := range before.Connections.AllDatabases() {
+		if _, ok := beforeDBs[db.Ref]; ok {
+			out = append(out, fmt.Sprintf("%s: database was removed", db.Ref))
+		}
+	}
+
+	return out
+}
+
+// commitMessage returns the message of the commit, listing the detected changes in its body
+func (g *GitOutput) commitMessage(changes []string) string {
+	if len(changes) == 0 {
+		return g.message
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n\n", g.message)
+	for i, c := range changes {
+		if i == gitMaxChanges {
+			fmt.Fprintf(&b, "- ... and %d more\n", len(changes)-i)
+			break
+		}
+		fmt.Fprintf(&b, "- %s\n", c)
+	}
+	return b.String()
+}
+
+// commitFile writes the body to the file and commits it, doing nothing if the file is unchanged.  Snapshots differing
+// only by the time they were captured are not committed.
+func (g *GitOutput) commitFile(body []byte) error {
+	changes := g.changes(body)
+	if changes != nil && len(changes) == 0 {
+		return nil
+	}
+
+	dest := filepath.Join(g.repo, g.path)
+	if err := os.MkdirAll(filepath.Dir(dest), 0777); err != nil {
+		return fmt.Errorf("error creating directory %q: %w", filepath.Dir(dest), err)
+	}
+	if err := os.WriteFile(dest, body, 0666); err != nil {
+		return fmt.Errorf("error writing file %q: %w", dest, err)
+	}
+
+	if _, err := g.git("add", "--", g.path); err != nil {
+		return err
+	}
+
+	// diff exits with status 1 when the staged file differs from HEAD
+	var ee *exec.ExitError
+	if _, err := g.git("diff", "--cached", "--quiet", "--", g.path); err == nil {
+		return nil
+	} else if !errors.As(err, &ee) || ee.ExitCode() != 1 {
+		return err
+	}
+
+	_, err := g.git("commit", "--quiet", "--message", g.commitMessage(changes), "--", g.path)
+	return err
+}
+
+// ahead returns true if HEAD contains commits missing from the remote branch pushed to, or if the remote branch is not
+// known locally
+func (g *GitOutput) ahead() bool {
+	branch := g.branch
+	if branch == "" {
+		out, err := g.git("symbolic-ref", "--quiet", "--short", "HEAD")
+		if err != nil {
+			return true
+		}
+		branch = strings.TrimSpace(out)
+	}
+
+	out, err := g.git("rev-list", "--count", "refs/remotes/"+g.remote+"/"+branch+"..HEAD")
+	if err != nil {
+		return true
+	}
+	return strings.TrimSpace(out) != "0"
+}
+
+// commit commits the body and, if pushing, pushes HEAD whenever it is ahead of the remote branch, so that a commit
+// whose push failed is pushed by the next run even if the file is then unchanged
+func (g *GitOutput) commit(body []byte) error {
+	if err := g.commitFile(body); err != nil {
+		return err
+	}
+
+	if !g.push || !g.ahead() {
+		return nil
+	}
+
+	ref := "HEAD"
+	if g.branch != "" {
+		ref = "HEAD:" + g.branch
+	}
+	if _, err := g.git("push", "--quiet", g.remote, ref); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// gitWriter buffers the rendered result until it is published
+type gitWriter struct {
+	out *GitOutput
+	buf bytes.Buffer
+}
+
+func (w *gitWriter) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+func (w *gitWriter) Publish() error {
+	return w.out.commit(w.buf.Bytes())
+}
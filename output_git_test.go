@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// runGit runs git within dir, failing the test if it fails
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// newGitRepos creates a working tree with an initial commit, tracking the main branch of a bare repository as origin,
+// and returns the paths of the working tree and the bare repository
+func newGitRepos(t *testing.T) (string, string) {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	// keep the user's and system's configuration out of the test
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", home)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	dir := t.TempDir()
+	work, bare := filepath.Join(dir, "work"), filepath.Join(dir, "remote.git")
+
+	runGit(t, dir, "init", "--quiet", "--bare", "--initial-branch=main", bare)
+	runGit(t, dir, "init", "--quiet", "--initial-branch=main", work)
+	runGit(t, work, "commit", "--quiet", "--allow-empty", "--message", "Initial commit")
+	runGit(t, work, "remote", "add", "origin", bare)
+	runGit(t, work, "push", "--quiet", "--set-upstream", "origin", "main")
+
+	return work, bare
+}
+
+func newTestGitOutput(t *testing.T, cfg map[string]string) *GitOutput {
+	t.Helper()
+
+	out, err := newGitOutput(cli.NewContext(nil, nil, nil), cfg)
+	if err != nil {
+		t.Fatalf("error building git output: %v", err)
+	}
+	return out.(*GitOutput)
+}
+
+// snapshotBody renders a JSON snapshot of the named schema file, labelled "prod"
+func snapshotBody(t *testing.T, file string, capturedAt time.Time) string {
+	t.Helper()
+
+	snap := newSnapshotFile(connectionSummaries{ddlSummary(t, "prod", file)})
+	snap.CapturedAt = capturedAt
+	snap.ToolVersion = "test"
+
+	var b bytes.Buffer
+	if err := (&SummaryJSONFormatter{pretty: true}).Render(snap, &b); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+func TestGitOutputCommit(t *testing.T) {
+	work, bare := newGitRepos(t)
+
+	out := newTestGitOutput(t, map[string]string{
+		"repo":    work,
+		"path":    "prod/shop.json",
+		"message": "Record prod schema",
+		"author":  "Schema Bot <dba@example.com>",
+		"push":    "true",
+	})
+
+	captured := time.Date(2024, 3, 9, 14, 5, 6, 0, time.UTC)
+
+	// the first snapshot is committed and pushed
+	if err := publish(t, out, snapshotBody(t, "base.sql", captured)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first := runGit(t, work, "rev-parse", "HEAD")
+	if got := runGit(t, work, "log", "-1", "--format=%s|%an <%ae>|%cn"); got != "Record prod schema|Schema Bot <dba@example.com>|Schema Bot" {
+		t.Errorf("unexpected commit %q", got)
+	}
+	if got := runGit(t, bare, "rev-parse", "main"); got != first {
+		t.Errorf("expected the commit to be pushed, remote is at %s", got)
+	}
+
+	// a snapshot differing only by the time it was captured is not committed
+	if err := publish(t, out, snapshotBody(t, "base.sql", captured.Add(24*time.Hour))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := runGit(t, work, "rev-parse", "HEAD"); got != first {
+		t.Errorf("expected no new commit, HEAD moved to %s", got)
+	}
+	if got := runGit(t, work, "status", "--porcelain"); got != "" {
+		t.Errorf("expected a clean working tree, saw %q", got)
+	}
+
+	// a changed schema is committed with the changes listed in the body
+	if err := publish(t, out, snapshotBody(t, "target.sql", captured.Add(48*time.Hour))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := runGit(t, work, "rev-list", "--count", "HEAD"); got != "3" {
+		t.Errorf("expected 3 commits, saw %s", got)
+	}
+
+	msg := runGit(t, work, "log", "-1", "--format=%B")
+	for _, want := range []string{
+		"Record prod schema\n\n- ",
+		"- `prod`.`shop`: table audit was added (BASE TABLE)",
+		"- `prod`.`shop`: column customers.name type changed from \"varchar(100)\" to \"varchar(120)\"",
+		"- `prod`.`shop`: function dbl was removed (FUNCTION)",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected commit message to contain %q:\n%s", want, msg)
+		}
+	}
+	if got, want := runGit(t, bare, "rev-parse", "main"), runGit(t, work, "rev-parse", "HEAD"); got != want {
+		t.Errorf("expected the commit to be pushed, remote is at %s", got)
+	}
+}
+
+func TestGitOutputPushRetry(t *testing.T) {
+	work, bare := newGitRepos(t)
+
+	out := newTestGitOutput(t, map[string]string{"repo": work, "path": "drift.txt", "push": "true", "branch": "main"})
+
+	// the commit is kept when the push fails
+	runGit(t, work, "remote", "set-url", "origin", filepath.Join(t.TempDir(), "missing.git"))
+	if err := publish(t, out, "drift\n"); err == nil {
+		t.Fatal("expected the push to fail")
+	}
+	head := runGit(t, work, "rev-parse", "HEAD")
+	if got := runGit(t, work, "log", "-1", "--format=%s"); got != "Update schema snapshot" {
+		t.Errorf("expected the default message, saw %q", got)
+	}
+
+	// the next run pushes it, although the file is unchanged
+	runGit(t, work, "remote", "set-url", "origin", bare)
+	if err := publish(t, out, "drift\n"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := runGit(t, work, "rev-parse", "HEAD"); got != head {
+		t.Errorf("expected no new commit, HEAD moved to %s", got)
+	}
+	if got := runGit(t, bare, "rev-parse", "main"); got != head {
+		t.Errorf("expected the earlier commit to be pushed, remote is at %s", got)
+	}
+}
+
+func TestGitOutputNoPush(t *testing.T) {
+	work, bare := newGitRepos(t)
+	initial := runGit(t, bare, "rev-parse", "main")
+
+	out := newTestGitOutput(t, map[string]string{"repo": work, "path": "reports/drift.txt"})
+	for _, body := range []string{"one\n", "one\n", "two\n"} {
+		if err := publish(t, out, body); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// files which are not snapshots are committed whenever their content changes
+	if got := runGit(t, work, "rev-list", "--count", "HEAD"); got != "3" {
+		t.Errorf("expected 3 commits, saw %s", got)
+	}
+	if got := runGit(t, work, "show", "HEAD:reports/drift.txt"); got != "two" {
+		t.Errorf("unexpected committed content %q", got)
+	}
+	if got := runGit(t, bare, "rev-parse", "main"); got != initial {
+		t.Errorf("expected nothing to be pushed, remote is at %s", got)
+	}
+}
+
+func TestGitOutputConfig(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	work, _ := newGitRepos(t)
+
+	tests := map[string]map[string]string{
+		"missing repo":   {"path": "x"},
+		"missing path":   {"repo": work},
+		"escaping path":  {"repo": work, "path": "../x"},
+		"absolute path":  {"repo": work, "path": "/tmp/x"},
+		"invalid push":   {"repo": work, "path": "x", "push": "maybe"},
+		"invalid author": {"repo": work, "path": "x", "author": "not an address"},
+		"not a repo":     {"repo": t.TempDir(), "path": "x"},
+	}
+
+	for name, cfg := range tests {
+		if _, err := newGitOutput(cli.NewContext(nil, nil, nil), cfg); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
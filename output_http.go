@@ -0,0 +1,291 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+const OutputHTTP = "http"
+
+// httpHeaderPrefix prefixes the config keys setting request headers, e.g. "header.Authorization=Bearer abc"
+const httpHeaderPrefix = "header."
+
+var (
+	_ Output      = (*HTTPOutput)(nil)
+	_ formatAware = (*HTTPOutput)(nil)
+	_ Publisher   = (*httpWriter)(nil)
+)
+
+// formatContentTypes lists the content type of each format's output.  Formats not listed are sent as plain text.
+var formatContentTypes = map[string]string{
+	FormatJSON:        "application/json",
+	FormatMarkdown:    "text/markdown; charset=utf-8",
+	FormatHTML:        "text/html; charset=utf-8",
+	FormatJUnit:       "application/xml",
+	FormatSARIF:       "application/sarif+json",
+	FormatCSV:         "text/csv; charset=utf-8",
+	FormatDOT:         "text/vnd.graphviz",
+	FormatOpenMetrics: "application/openmetrics-text; version=1.0.0; charset=utf-8",
+	SummaryFormatYAML: "application/yaml",
+	SummaryFormatTOML: "application/toml",
+}
+
+// HTTPOutput sends the rendered result as the body of a single HTTP request once rendering has completed.  Failed
+// requests are retried with exponential backoff when the failure may be temporary.
+type HTTPOutput struct {
+	ctx         context.Context
+	url         string
+	redacted    string
+	method      string
+	contentType string
+	headers     http.Header
+	timeout     time.Duration
+	retries     int
+	backoff     time.Duration
+	hmacSecret  []byte
+	hmacHeader  string
+
+	// contentTypeSet is true if the content type was configured, rather than derived from the format
+	contentTypeSet bool
+}
+
+func newHTTPOutput(cctx *cli.Context, cfg map[string]string) (Output, error) {
+	var err error
+
+	ho := HTTPOutput{
+		ctx:         cctx.Context,
+		method:      http.MethodPost,
+		contentType: "text/plain; charset=utf-8",
+		headers:     make(http.Header),
+		timeout:     30 * time.Second,
+		retries:     3,
+		backoff:     time.Second,
+		hmacHeader:  "X-Signature-256",
+	}
+
+	if v, ok := cfg["url"]; !ok {
+		return nil, fmt.Errorf("output %q requires config key %q to be set", OutputHTTP, "url")
+	} else if u, err := url.Parse(v); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		// the value is omitted as webhook URLs commonly embed a secret
+		return nil, errors.New("unable to parse \"url\" value as an http or https URL")
+	} else {
+		ho.url, ho.redacted = v, redactURL(u)
+	}
+
+	if v, ok := cfg["method"]; ok {
+		switch m := strings.ToUpper(v); m {
+		case http.MethodPost, http.MethodPut:
+			ho.method = m
+		default:
+			return nil, fmt.Errorf("unknown \"method\" value %q specified, expected one of %v", v, []string{http.MethodPost, http.MethodPut})
+		}
+	}
+
+	if v, ok := cfg["content-type"]; ok {
+		ho.contentType, ho.contentTypeSet = v, true
+	}
+
+	for k, v := range cfg {
+		if name, ok := strings.CutPrefix(k, httpHeaderPrefix); ok && name != "" {
+			ho.headers.Add(name, v)
+		}
+	}
+
+	if v, ok := cfg["timeout"]; ok {
+		if ho.timeout, err = time.ParseDuration(v); err != nil {
+			return nil, fmt.Errorf("unable to parse \"timeout\" value %q as duration: %w", v, err)
+		} else if ho.timeout <= 0 {
+			return nil, fmt.Errorf("\"timeout\" must be positive, saw %s", ho.timeout)
+		}
+	}
+
+	if v, ok := cfg["retries"]; ok {
+		if ho.retries, err = strconv.Atoi(v); err != nil {
+			return nil, fmt.Errorf("unable to parse \"retries\" value %q as int: %w", v, err)
+		} else if ho.retries < 0 {
+			return nil, fmt.Errorf("\"retries\" must not be negative, saw %d", ho.retries)
+		}
+	}
+
+	if v, ok := cfg["backoff"]; ok {
+		if ho.backoff, err = time.ParseDuration(v); err != nil {
+			return nil, fmt.Errorf("unable to parse \"backoff\" value %q as duration: %w", v, err)
+		} else if ho.backoff < 0 {
+			return nil, fmt.Errorf("\"backoff\" must not be negative, saw %s", ho.backoff)
+		}
+	}
+
+	// the secret may be read from the environment to keep it out of the process list
+	if v, ok := cfg["hmac-secret"]; ok {
+		ho.hmacSecret = []byte(v)
+	}
+	if v, ok := cfg["hmac-secret-env"]; ok {
+		if s := os.Getenv(v); s == "" {
+			return nil, fmt.Errorf("environment variable %q named by \"hmac-secret-env\" is empty", v)
+		} else {
+			ho.hmacSecret = []byte(s)
+		}
+	}
+	if v, ok := cfg["hmac-header"]; ok {
+		ho.hmacHeader = v
+	}
+
+	return &ho, nil
+}
+
+func (*HTTPOutput) Type() string {
+	return OutputHTTP
+}
+
+// redactURL returns the scheme and host of a URL, omitting the credentials, path and query which may contain secrets
+func redactURL(u *url.URL) string {
+	return (&url.URL{Scheme: u.Scheme, Host: u.Host}).String()
+}
+
+// setFormat sets the content type to that of the format, unless one was configured
+func (h *HTTPOutput) setFormat(format string) {
+	if ct, ok := formatContentTypes[format]; ok && !h.contentTypeSet {
+		h.contentType = ct
+	}
+}
+
+func (h *HTTPOutput) Writer() (io.Writer, error) {
+	return &httpWriter{out: h}, nil
+}
+
+// httpStatusError is returned for responses with a non-2xx status
+type httpStatusError struct {
+	Status     string
+	Code       int
+	Body       string
+	RetryAfter time.Duration
+}
+
+func (e *httpStatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("unexpected response status %q", e.Status)
+	}
+	return fmt.Sprintf("unexpected response status %q: %s", e.Status, e.Body)
+}
+
+// retryable returns true if a request failing with the provided error may succeed if sent again
+func (h *HTTPOutput) retryable(err error) bool {
+	if h.ctx.Err() != nil {
+		return false
+	}
+	var se *httpStatusError
+	if errors.As(err, &se) {
+		return se.Code == http.StatusRequestTimeout || se.Code == http.StatusTooManyRequests || se.Code >= 500
+	}
+	return true
+}
+
+// sign returns the value of the signature header: the hex-encoded HMAC-SHA256 of the body, prefixed with "sha256="
+func (h *HTTPOutput) sign(body []byte) string {
+	mac := hmac.New(sha256.New, h.hmacSecret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// send makes a single attempt at delivering the body
+func (h *HTTPOutput) send(body []byte) error {
+	ctx, cancel := context.WithTimeout(h.ctx, h.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, h.method, h.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error building request: %w", err)
+	}
+
+	req.Header = h.headers.Clone()
+	req.Header.Set("Content-Type", h.contentType)
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", "mysql-diff/"+toolVersion())
+	}
+	if len(h.hmacSecret) > 0 {
+		req.Header.Set(h.hmacHeader, h.sign(body))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		var ue *url.Error
+		if errors.As(err, &ue) {
+			ue.URL = h.redacted
+		}
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	// a short excerpt of the response is included in errors, and the remainder drained so the connection is reused
+	excerpt, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	se := &httpStatusError{
+		Status: resp.Status,
+		Code:   resp.StatusCode,
+		Body:   strings.TrimSpace(string(excerpt)),
+	}
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+		se.RetryAfter = time.Duration(secs) * time.Second
+	}
+	return se
+}
+
+// deliver sends the body, retrying temporary failures after a delay which doubles with every attempt.  A delay
+// requested by the server with Retry-After is honored instead.  Errors only include the redacted URL.
+func (h *HTTPOutput) deliver(body []byte) error {
+	for attempt := 0; ; attempt++ {
+		err := h.send(body)
+		if err == nil {
+			return nil
+		}
+		if attempt >= h.retries || !h.retryable(err) {
+			return fmt.Errorf("error sending %s request to %q after %d attempt(s): %w", h.method, h.redacted, attempt+1, err)
+		}
+
+		wait := h.backoff << attempt
+		var se *httpStatusError
+		if errors.As(err, &se) && se.RetryAfter > 0 {
+			wait = se.RetryAfter
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-h.ctx.Done():
+			return fmt.Errorf("error sending %s request to %q: %w", h.method, h.redacted, h.ctx.Err())
+		}
+	}
+}
+
+// httpWriter buffers the rendered result until it is published
+type httpWriter struct {
+	out *HTTPOutput
+	buf bytes.Buffer
+}
+
+func (w *httpWriter) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+func (w *httpWriter) Publish() error {
+	return w.out.deliver(w.buf.Bytes())
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// httpRequest records a request received by a test server
+type httpRequest struct {
+	Method  string
+	Path    string
+	Headers http.Header
+	Body    string
+}
+
+// httpRecorder is a test server handler responding with the queued statuses in turn, then 204
+type httpRecorder struct {
+	mu       sync.Mutex
+	statuses []int
+	headers  []http.Header
+	requests []httpRequest
+}
+
+func (hr *httpRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	hr.mu.Lock()
+	defer hr.mu.Unlock()
+
+	hr.requests = append(hr.requests, httpRequest{Method: r.Method, Path: r.URL.RequestURI(), Headers: r.Header.Clone(), Body: string(body)})
+
+	status := http.StatusNoContent
+	if len(hr.statuses) > 0 {
+		status, hr.statuses = hr.statuses[0], hr.statuses[1:]
+	}
+	if len(hr.headers) > 0 {
+		for k, vs := range hr.headers[0] {
+			w.Header()[k] = vs
+		}
+		hr.headers = hr.headers[1:]
+	}
+	w.WriteHeader(status)
+	if status >= 300 {
+		_, _ = io.WriteString(w, "nope")
+	}
+}
+
+func (hr *httpRecorder) Requests() []httpRequest {
+	hr.mu.Lock()
+	defer hr.mu.Unlock()
+	return append([]httpRequest(nil), hr.requests...)
+}
+
+func newTestHTTPOutput(t *testing.T, cfg map[string]string) *HTTPOutput {
+	t.Helper()
+
+	out, err := newHTTPOutput(cli.NewContext(nil, nil, nil), cfg)
+	if err != nil {
+		t.Fatalf("error building http output: %v", err)
+	}
+	return out.(*HTTPOutput)
+}
+
+// publish writes body to the output and publishes it
+func publish(t *testing.T, out Output, body string) error {
+	t.Helper()
+
+	w, err := out.Writer()
+	if err != nil {
+		t.Fatalf("error opening writer: %v", err)
+	}
+	if _, err = io.WriteString(w, body); err != nil {
+		t.Fatalf("error writing: %v", err)
+	}
+	return w.(Publisher).Publish()
+}
+
+func TestHTTPOutputDeliver(t *testing.T) {
+	rec := &httpRecorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	out := newTestHTTPOutput(t, map[string]string{
+		"url":           srv.URL + "/hooks/schema?token=abc",
+		"method":        "put",
+		"header.X-Team": "dba",
+		"hmac-secret":   "s3cret",
+	})
+	out.setFormat(FormatJSON)
+
+	if err := publish(t, out, `{"ok":true}`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reqs := rec.Requests()
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 request, saw %d", len(reqs))
+	}
+	req := reqs[0]
+
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write([]byte(`{"ok":true}`))
+	wantSig := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	checks := []struct{ name, got, want string }{
+		{"method", req.Method, http.MethodPut},
+		{"path", req.Path, "/hooks/schema?token=abc"},
+		{"body", req.Body, `{"ok":true}`},
+		{"content type", req.Headers.Get("Content-Type"), "application/json"},
+		{"header", req.Headers.Get("X-Team"), "dba"},
+		{"signature", req.Headers.Get("X-Signature-256"), wantSig},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s: got %q, want %q", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestHTTPOutputSignatureHeader(t *testing.T) {
+	rec := &httpRecorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	t.Setenv("MYSQL_DIFF_TEST_SECRET", "from-env")
+
+	out := newTestHTTPOutput(t, map[string]string{
+		"url":             srv.URL,
+		"hmac-secret-env": "MYSQL_DIFF_TEST_SECRET",
+		"hmac-header":     "X-Hub-Signature-256",
+		"content-type":    "text/x-custom",
+	})
+	out.setFormat(FormatJSON)
+
+	if err := publish(t, out, "body"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req := rec.Requests()[0]
+	mac := hmac.New(sha256.New, []byte("from-env"))
+	mac.Write([]byte("body"))
+	if got, want := req.Headers.Get("X-Hub-Signature-256"), "sha256="+hex.EncodeToString(mac.Sum(nil)); got != want {
+		t.Errorf("signature: got %q, want %q", got, want)
+	}
+	if got := req.Headers.Get("X-Signature-256"); got != "" {
+		t.Errorf("expected no default signature header, saw %q", got)
+	}
+	if got := req.Headers.Get("Content-Type"); got != "text/x-custom" {
+		t.Errorf("expected the configured content type to be kept, saw %q", got)
+	}
+}
+
+func TestHTTPOutputRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		retries  string
+		attempts int
+		fail     bool
+	}{
+		{"retried until success", []int{500, 503}, "3", 3, false},
+		{"retries exhausted", []int{500, 502, 504}, "2", 3, true},
+		{"rate limited", []int{429}, "1", 2, false},
+		{"not retryable", []int{400}, "3", 1, true},
+		{"no retries", []int{500}, "0", 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &httpRecorder{statuses: tt.statuses}
+			srv := httptest.NewServer(rec)
+			defer srv.Close()
+
+			out := newTestHTTPOutput(t, map[string]string{"url": srv.URL, "retries": tt.retries, "backoff": "1ms"})
+
+			err := publish(t, out, "body")
+			if tt.fail != (err != nil) {
+				t.Errorf("expected failure %t, saw %v", tt.fail, err)
+			}
+			if got := len(rec.Requests()); got != tt.attempts {
+				t.Errorf("expected %d attempt(s), saw %d", tt.attempts, got)
+			}
+		})
+	}
+}
+
+func TestHTTPOutputBackoff(t *testing.T) {
+	rec := &httpRecorder{statuses: []int{500, 500, 500}}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	out := newTestHTTPOutput(t, map[string]string{"url": srv.URL, "retries": "2", "backoff": "25ms"})
+
+	start := time.Now()
+	if err := publish(t, out, "body"); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	// 25ms before the first retry, and 50ms before the second
+	if elapsed := time.Since(start); elapsed < 75*time.Millisecond {
+		t.Errorf("expected the delay to double with each retry, finished after %s", elapsed)
+	}
+}
+
+func TestHTTPOutputRetryAfter(t *testing.T) {
+	rec := &httpRecorder{
+		statuses: []int{503},
+		headers:  []http.Header{{"Retry-After": []string{"1"}}},
+	}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	// the server's delay is honored instead of the much longer backoff
+	out := newTestHTTPOutput(t, map[string]string{"url": srv.URL, "retries": "1", "backoff": "1h"})
+
+	start := time.Now()
+	if err := publish(t, out, "body"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < time.Second || elapsed > 30*time.Second {
+		t.Errorf("expected to retry after the requested second, finished after %s", elapsed)
+	}
+	if got := len(rec.Requests()); got != 2 {
+		t.Errorf("expected 2 attempts, saw %d", got)
+	}
+}
+
+func TestHTTPOutputRedactsURL(t *testing.T) {
+	rec := &httpRecorder{statuses: []int{403}}
+	srv := httptest.NewServer(rec)
+
+	const secret = "/services/T000/B000/XXXXSECRET?token=hunter2"
+
+	out := newTestHTTPOutput(t, map[string]string{"url": srv.URL + secret, "retries": "0"})
+	err := publish(t, out, "body")
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Fatalf("expected a status error, saw %v", err)
+	}
+
+	srv.Close()
+	connErr := publish(t, out, "body")
+	if connErr == nil {
+		t.Fatal("expected a connection error")
+	}
+
+	for _, e := range []error{err, connErr} {
+		msg := e.Error()
+		if strings.Contains(msg, "SECRET") || strings.Contains(msg, "hunter2") {
+			t.Errorf("error includes the URL path or query: %s", msg)
+		}
+		if !strings.Contains(msg, srv.URL) {
+			t.Errorf("expected error to include the scheme and host %q: %s", srv.URL, msg)
+		}
+	}
+
+	if _, err = newHTTPOutput(cli.NewContext(nil, nil, nil), map[string]string{"url": "ftp://example.com" + secret}); err == nil {
+		t.Error("expected a non-http URL to be rejected")
+	} else if strings.Contains(err.Error(), "hunter2") {
+		t.Errorf("error includes the URL: %s", err)
+	}
+}
+
+func TestHTTPOutputConfig(t *testing.T) {
+	tests := map[string]map[string]string{
+		"missing url":      {},
+		"relative url":     {"url": "/hooks"},
+		"unknown method":   {"url": "http://example.com", "method": "PATCH"},
+		"invalid timeout":  {"url": "http://example.com", "timeout": "soon"},
+		"invalid retries":  {"url": "http://example.com", "retries": "many"},
+		"negative retries": {"url": "http://example.com", "retries": "-1"},
+		"zero timeout":     {"url": "http://example.com", "timeout": "0s"},
+		"negative timeout": {"url": "http://example.com", "timeout": "-1s"},
+		"invalid backoff":  {"url": "http://example.com", "backoff": "1"},
+		"negative backoff": {"url": "http://example.com", "backoff": "-1s"},
+		"empty secret env": {"url": "http://example.com", "hmac-secret-env": "MYSQL_DIFF_TEST_UNSET"},
+	}
+
+	for name, cfg := range tests {
+		if _, err := newHTTPOutput(cli.NewContext(nil, nil, nil), cfg); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+const OutputSyslog = "syslog"
+
+const (
+	syslogUDP      = "udp"
+	syslogTCP      = "tcp"
+	syslogUnix     = "unix"
+	syslogUnixgram = "unixgram"
+	syslogJournald = "journald"
+)
+
+var (
+	_ Output         = (*SyslogOutput)(nil)
+	_ summariesAware = (*SyslogOutput)(nil)
+	_ Publisher      = (*syslogWriter)(nil)
+)
+
+// syslogFacilities maps facility names to their codes
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7, "uucp": 8, "cron": 9,
+	"authpriv": 10, "ftp": 11, "local0": 16, "local1": 17, "local2": 18, "local3": 19, "local4": 20, "local5": 21,
+	"local6": 22, "local7": 23,
+}
+
+// syslogSeverities lists the severity names, in order of their codes
+var syslogSeverities = []string{"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug"}
+
+// sdNamePattern matches valid structured data IDs
+var sdNamePattern = regexp.MustCompile(`^[!-~]{1,32}$`)
+
+// SyslogOutput sends a structured event for every difference found once rendering has completed, either as RFC 5424
+// messages with structured data or as native journald entries.  The rendered result itself is discarded.
+type SyslogOutput struct {
+	network  string
+	address  string
+	facility int
+	severity int
+	appName  string
+	hostname string
+	sdID     string
+	timeout  time.Duration
+
+	summaries connectionSummaries
+}
+
+func newSyslogOutput(_ *cli.Context, cfg map[string]string) (Output, error) {
+	so := SyslogOutput{
+		network:  syslogUDP,
+		facility: syslogFacilities["user"],
+		severity: slices.Index(syslogSeverities, "notice"),
+		appName:  "mysql-diff",
+		sdID:     "drift@32473",
+		timeout:  10 * time.Second,
+	}
+
+	if v, ok := cfg["network"]; ok {
+		switch v {
+		case syslogUDP, syslogTCP, syslogUnix, syslogUnixgram, syslogJournald:
+			so.network = v
+		default:
+			return nil, fmt.Errorf("unknown \"network\" value %q specified, expected one of %v", v, []string{syslogUDP, syslogTCP, syslogUnix, syslogUnixgram, syslogJournald})
+		}
+	}
+
+	switch v, ok := cfg["address"]; {
+	case ok:
+		so.address = v
+	case so.network == syslogJournald:
+		so.address = "/run/systemd/journal/socket"
+	case so.network == syslogUnix || so.network == syslogUnixgram:
+		so.address = "/dev/log"
+	default:
+		return nil, fmt.Errorf("output %q requires config key %q to be set with network %q", OutputSyslog, "address", so.network)
+	}
+
+	if v, ok := cfg["facility"]; ok {
+		if f, ok := syslogFacilities[v]; !ok {
+			return nil, fmt.Errorf("unknown \"facility\" value %q specified", v)
+		} else {
+			so.facility = f
+		}
+	}
+
+	if v, ok := cfg["severity"]; ok {
+		if so.severity = slices.Index(syslogSeverities, v); so.severity == -1 {
+			return nil, fmt.Errorf("unknown \"severity\" value %q specified, expected one of %v", v, syslogSeverities)
+		}
+	}
+
+	if v, ok := cfg["app-name"]; ok {
+		so.appName = v
+	}
+
+	if v, ok := cfg["hostname"]; ok {
+		so.hostname = v
+	} else if h, err := os.Hostname(); err == nil {
+		so.hostname = h
+	}
+
+	if v, ok := cfg["sd-id"]; ok {
+		if !sdNamePattern.MatchString(v) || strings.ContainsAny(v, `= ]"`) {
+			return nil, fmt.Errorf("\"sd-id\" value %q is not a valid structured data ID", v)
+		}
+		so.sdID = v
+	}
+
+	if v, ok := cfg["timeout"]; ok {
+		var err error
+		if so.timeout, err = time.ParseDuration(v); err != nil {
+			return nil, fmt.Errorf("unable to parse \"timeout\" value %q as duration: %w", v, err)
+		} else if so.timeout <= 0 {
+			return nil, fmt.Errorf("\"timeout\" must be positive, saw %s", so.timeout)
+		}
+	}
+
+	return &so, nil
+}
+
+func (*SyslogOutput) Type() string {
+	return OutputSyslog
+}
+
+// setSummaries sets the summaries events are sent for
+func (s *SyslogOutput) setSummaries(summaries connectionSummaries) {
+	s.summaries = summaries
+}
+
+func (s *SyslogOutput) Writer() (io.Writer, error) {
+	return &syslogWriter{out: s, summaries: s.summaries}, nil
+}
+
+// syslogEvent is a single difference, along with the databases it was found between
+type syslogEvent struct {
+	Diff     *databaseDiff
+	Object   objectDiff
+	Captured time.Time
+}
+
+// fields returns the structured fields of the event, in order
+func (e syslogEvent) fields() [][2]string {
+	out := [][2]string{
+		{"source", e.Diff.Target.Connection},
+		{"database", e.Diff.Target.Database},
+		{"baseline_source", e.Diff.Baseline.Connection},
+		{"baseline_database", e.Diff.Baseline.Database},
+		{"kind", string(e.Object.Kind)},
+		{"object", string(e.Object.Object)},
+	}
+	for _, f := range [][2]string{
+		{"table", e.Object.TableName()},
+		{"column", e.Object.ColumnName()},
+		{"name", e.Object.Name},
+		{"property", e.Object.Property},
+	} {
+		if f[1] != "" {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// message returns the human-readable message of the event
+func (e syslogEvent) message() string {
+	return fmt.Sprintf("%s: %s", e.Diff.Target, e.Object)
+}
+
+// syslogHeader returns a header field, replacing characters RFC 5424 does not allow and truncating it to limit bytes
+func syslogHeader(v string, limit int) string {
+	if v == "" {
+		return "-"
+	}
+	b := []byte(v)
+	for i, c := range b {
+		if c < '!' || c > '~' {
+			b[i] = '_'
+		}
+	}
+	if len(b) > limit {
+		b = b[:limit]
+	}
+	return string(b)
+}
+
+// formatRFC5424 returns the event as an RFC 5424 message
+func (s *SyslogOutput) formatRFC5424(e syslogEvent) []byte {
+	var b bytes.Buffer
+
+	fmt.Fprintf(
+		&b,
+		"<%d>1 %s %s %s %d %s [%s",
+		s.facility*8+s.severity,
+		e.Captured.Format("2006-01-02T15:04:05.000000Z07:00"),
+		syslogHeader(s.hostname, 255),
+		syslogHeader(s.appName, 48),
+		os.Getpid(),
+		syslogHeader(string(e.Object.Kind), 32),
+		s.sdID,
+	)
+	for _, f := range e.fields() {
+		fmt.Fprintf(&b, ` %s="%s"`, f[0], strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(f[1]))
+	}
+	b.WriteString("] ")
+	b.WriteString(e.message())
+
+	return b.Bytes()
+}
+
+// journalField appends a field in the journald native protocol, using the binary form for values spanning lines
+func journalField(b *bytes.Buffer, name, value string) {
+	if !strings.Contains(value, "\n") {
+		fmt.Fprintf(b, "%s=%s\n", name, value)
+		return
+	}
+	b.WriteString(name + "\n")
+	_ = binary.Write(b, binary.LittleEndian, uint64(len(value)))
+	b.WriteString(value + "\n")
+}
+
+// formatJournald returns the event as a journald native protocol entry, with fields prefixed by MYSQL_DIFF_
+func (s *SyslogOutput) formatJournald(e syslogEvent) []byte {
+	var b bytes.Buffer
+
+	journalField(&b, "MESSAGE", e.message())
+	journalField(&b, "PRIORITY", strconv.Itoa(s.severity))
+	journalField(&b, "SYSLOG_FACILITY", strconv.Itoa(s.facility))
+	journalField(&b, "SYSLOG_IDENTIFIER", s.appName)
+	for _, f := range e.fields() {
+		journalField(&b, "MYSQL_DIFF_"+strings.ToUpper(f[0]), f[1])
+	}
+
+	return b.Bytes()
+}
+
+// send delivers every event over a single connection
+func (s *SyslogOutput) send(events []syslogEvent) error {
+	network := s.network
+	if network == syslogJournald {
+		network = syslogUnixgram
+	}
+
+	conn, err := net.DialTimeout(network, s.address, s.timeout)
+	if err != nil {
+		return fmt.Errorf("error connecting to %s %q: %w", s.network, s.address, err)
+	}
+
+	defer func() { _ = conn.Close() }()
+
+	if err = conn.SetWriteDeadline(time.Now().Add(s.timeout)); err != nil {
+		return fmt.Errorf("error setting write deadline: %w", err)
+	}
+
+	for _, e := range events {
+		var msg []byte
+		switch s.network {
+		case syslogJournald:
+			msg = s.formatJournald(e)
+		case syslogTCP, syslogUnix:
+			// stream transports frame messages with octet counting, as described by RFC 6587
+			m := s.formatRFC5424(e)
+			msg = append([]byte(strconv.Itoa(len(m))+" "), m...)
+		default:
+			msg = s.formatRFC5424(e)
+		}
+
+		if _, err = conn.Write(msg); err != nil {
+			return fmt.Errorf("error sending event to %s %q: %w", s.network, s.address, err)
+		}
+	}
+
+	return nil
+}
+
+// syslogWriter discards the rendered result, sending events for the differences between the summaries once rendering
+// has completed
+type syslogWriter struct {
+	out       *SyslogOutput
+	summaries connectionSummaries
+}
+
+func (w *syslogWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (w *syslogWriter) Publish() error {
+	now := time.Now()
+
+	events := make([]syslogEvent, 0)
+	for _, dd := range buildDiff(w.summaries).Comparisons {
+		for _, od := range dd.Differences {
+			events = append(events, syslogEvent{Diff: dd, Object: od, Captured: now})
+		}
+	}
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	return w.out.send(events)
+}
@@ -0,0 +1,341 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// syslogSocketPath returns the path of a socket within a new temporary directory, short enough for the limit on unix
+// socket paths
+func syslogSocketPath(t *testing.T) string {
+	t.Helper()
+
+	dir, err := os.MkdirTemp("", "syslog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	return filepath.Join(dir, "s.sock")
+}
+
+// syslogSummaries returns the fixture summaries, with a target label requiring escaping in structured data
+func syslogSummaries(t *testing.T) connectionSummaries {
+	t.Helper()
+
+	summaries := fixtureSummaries(t)
+	summaries[1].Label = `tar"get]\x`
+	return summaries
+}
+
+func newTestSyslogOutput(t *testing.T, cfg map[string]string) *SyslogOutput {
+	t.Helper()
+
+	out, err := newSyslogOutput(nil, cfg)
+	if err != nil {
+		t.Fatalf("error building syslog output: %v", err)
+	}
+	return out.(*SyslogOutput)
+}
+
+// sendEvents renders the summaries into the output, sending an event for every difference
+func sendEvents(t *testing.T, out *SyslogOutput, summaries connectionSummaries) {
+	t.Helper()
+
+	err := renderDatabases(out, summaries, true, func(connectionSummaries, io.Writer) error { return nil })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// readPackets reads n packets from a datagram listener
+func readPackets(t *testing.T, pc net.PacketConn, n int) []string {
+	t.Helper()
+
+	out := make([]string, 0, n)
+	buf := make([]byte, 64*1024)
+	for range n {
+		if err := pc.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+			t.Fatal(err)
+		}
+		l, _, err := pc.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("error reading packet %d of %d: %v", len(out)+1, n, err)
+		}
+		out = append(out, string(buf[:l]))
+	}
+	return out
+}
+
+// readFrames accepts a single connection and reads every RFC 6587 octet-counted frame sent over it
+func readFrames(t *testing.T, l net.Listener) <-chan []string {
+	t.Helper()
+
+	ch := make(chan []string, 1)
+	go func() {
+		defer close(ch)
+
+		conn, err := l.Accept()
+		if err != nil {
+			t.Errorf("error accepting connection: %v", err)
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		out := make([]string, 0)
+		r := bufio.NewReader(conn)
+		for {
+			count, err := r.ReadString(' ')
+			if errors.Is(err, io.EOF) && count == "" {
+				break
+			} else if err != nil {
+				t.Errorf("error reading frame length: %v", err)
+				return
+			}
+			n, err := strconv.Atoi(strings.TrimSuffix(count, " "))
+			if err != nil {
+				t.Errorf("invalid frame length %q: %v", count, err)
+				return
+			}
+			msg := make([]byte, n)
+			if _, err = io.ReadFull(r, msg); err != nil {
+				t.Errorf("error reading frame of %d bytes: %v", n, err)
+				return
+			}
+			out = append(out, string(msg))
+		}
+		ch <- out
+	}()
+	return ch
+}
+
+// checkRFC5424 checks that every message carries the expected header and structured data, and that together they
+// describe every difference between the fixture schemas
+func checkRFC5424(t *testing.T, msgs []string, want int) {
+	t.Helper()
+
+	if len(msgs) != want {
+		t.Fatalf("expected %d messages, saw %d", want, len(msgs))
+	}
+
+	header := fmt.Sprintf("<134>1 %%s db-host.example mysql-diff %d %%s [drift@32473 %s", os.Getpid(),
+		`source="tar\"get\]\\x" database="shop" baseline_source="base" baseline_database="shop" kind="`)
+
+	for _, msg := range msgs {
+		// the timestamp and message ID vary between messages
+		parts := strings.SplitN(msg, " ", 7)
+		if len(parts) != 7 {
+			t.Errorf("malformed message %q", msg)
+			continue
+		}
+		if _, err := time.Parse(time.RFC3339Nano, parts[1]); err != nil {
+			t.Errorf("invalid timestamp %q: %v", parts[1], err)
+		}
+		if prefix := fmt.Sprintf(header, parts[1], parts[5]); !strings.HasPrefix(msg, prefix) {
+			t.Errorf("expected message to start with %q, saw %q", prefix, msg)
+		}
+	}
+
+	joined := strings.Join(msgs, "\n")
+	for _, want := range []string{
+		` kind="changed" object="column" table="customers" column="name" name="name" property="type"] ` +
+			"`tar\"get]\\x`.`shop`: column customers.name type changed from \"varchar(100)\" to \"varchar(120)\"",
+		` kind="added" object="table" table="audit" name="audit"] `,
+		` kind="removed" object="function" name="dbl"] `,
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("expected a message containing %q", want)
+		}
+	}
+}
+
+// syslogConfig returns the config shared by the transport tests
+func syslogConfig(network, address string) map[string]string {
+	return map[string]string{
+		"network":  network,
+		"address":  address,
+		"facility": "local0",
+		"severity": "info",
+		"hostname": "db-host.example",
+	}
+}
+
+func TestSyslogOutputTransports(t *testing.T) {
+	summaries := syslogSummaries(t)
+	want := len(buildDiff(summaries).Comparisons[0].Differences)
+
+	t.Run(syslogUDP, func(t *testing.T) {
+		pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = pc.Close() }()
+
+		sendEvents(t, newTestSyslogOutput(t, syslogConfig(syslogUDP, pc.LocalAddr().String())), summaries)
+		checkRFC5424(t, readPackets(t, pc, want), want)
+	})
+
+	t.Run(syslogUnixgram, func(t *testing.T) {
+		path := syslogSocketPath(t)
+		pc, err := net.ListenPacket("unixgram", path)
+		if err != nil {
+			t.Skipf("unix datagram sockets are not supported: %v", err)
+		}
+		defer func() { _ = pc.Close() }()
+
+		sendEvents(t, newTestSyslogOutput(t, syslogConfig(syslogUnixgram, path)), summaries)
+		checkRFC5424(t, readPackets(t, pc, want), want)
+	})
+
+	t.Run(syslogTCP, func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = l.Close() }()
+
+		frames := readFrames(t, l)
+		sendEvents(t, newTestSyslogOutput(t, syslogConfig(syslogTCP, l.Addr().String())), summaries)
+		checkRFC5424(t, <-frames, want)
+	})
+
+	t.Run(syslogUnix, func(t *testing.T) {
+		path := syslogSocketPath(t)
+		l, err := net.Listen("unix", path)
+		if err != nil {
+			t.Skipf("unix sockets are not supported: %v", err)
+		}
+		defer func() { _ = l.Close() }()
+
+		frames := readFrames(t, l)
+		sendEvents(t, newTestSyslogOutput(t, syslogConfig(syslogUnix, path)), summaries)
+		checkRFC5424(t, <-frames, want)
+	})
+}
+
+func TestSyslogOutputJournald(t *testing.T) {
+	path := syslogSocketPath(t)
+	pc, err := net.ListenPacket("unixgram", path)
+	if err != nil {
+		t.Skipf("unix datagram sockets are not supported: %v", err)
+	}
+	defer func() { _ = pc.Close() }()
+
+	summaries := fixtureSummaries(t)
+	want := len(buildDiff(summaries).Comparisons[0].Differences)
+
+	sendEvents(t, newTestSyslogOutput(t, map[string]string{"network": syslogJournald, "address": path, "severity": "warning"}), summaries)
+
+	entries := readPackets(t, pc, want)
+	for _, entry := range entries {
+		for _, field := range []string{
+			"\nPRIORITY=4\n",
+			"\nSYSLOG_FACILITY=1\n",
+			"\nSYSLOG_IDENTIFIER=mysql-diff\n",
+			"\nMYSQL_DIFF_SOURCE=target\n",
+			"\nMYSQL_DIFF_BASELINE_DATABASE=shop\n",
+		} {
+			if !strings.Contains(entry, field) {
+				t.Errorf("expected entry to contain %q:\n%s", field, entry)
+			}
+		}
+		if !strings.HasPrefix(entry, "MESSAGE=`target`.`shop`: ") {
+			t.Errorf("expected entry to start with the message:\n%s", entry)
+		}
+	}
+}
+
+func TestJournalField(t *testing.T) {
+	var b bytes.Buffer
+	journalField(&b, "SINGLE", "one line")
+	journalField(&b, "MULTI", "two\nlines")
+
+	var want bytes.Buffer
+	want.WriteString("SINGLE=one line\nMULTI\n")
+	_ = binary.Write(&want, binary.LittleEndian, uint64(9))
+	want.WriteString("two\nlines\n")
+
+	if !bytes.Equal(b.Bytes(), want.Bytes()) {
+		t.Errorf("got %q, want %q", b.Bytes(), want.Bytes())
+	}
+}
+
+func TestSyslogHeader(t *testing.T) {
+	tests := []struct {
+		v     string
+		limit int
+		want  string
+	}{
+		{"", 10, "-"},
+		{"host", 10, "host"},
+		{"my host\tname", 32, "my_host_name"},
+		{"héllo", 32, "h__llo"},
+		{"mysql-diff", 5, "mysql"},
+	}
+
+	for _, tt := range tests {
+		if got := syslogHeader(tt.v, tt.limit); got != tt.want {
+			t.Errorf("syslogHeader(%q, %d): got %q, want %q", tt.v, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestSyslogOutputNoDrift(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = pc.Close() }()
+
+	base := ddlSummary(t, "base", "base.sql")
+	copied := ddlSummary(t, "copy", "base.sql")
+	sendEvents(t, newTestSyslogOutput(t, syslogConfig(syslogUDP, pc.LocalAddr().String())), connectionSummaries{base, copied})
+
+	if err = pc.SetReadDeadline(time.Now().Add(100 * time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = pc.ReadFrom(make([]byte, 1024)); err == nil {
+		t.Error("expected nothing to be sent without differences")
+	}
+}
+
+func TestSyslogOutputConfig(t *testing.T) {
+	tests := map[string]map[string]string{
+		"unknown network":  {"network": "sctp", "address": "x"},
+		"missing address":  {"network": syslogUDP},
+		"unknown facility": {"address": "x", "facility": "printer"},
+		"unknown severity": {"address": "x", "severity": "catastrophic"},
+		"sd-id with space": {"address": "x", "sd-id": "drift check"},
+		"sd-id with equal": {"address": "x", "sd-id": "a=b"},
+		"long sd-id":       {"address": "x", "sd-id": strings.Repeat("a", 33)},
+		"invalid timeout":  {"address": "x", "timeout": "10"},
+		"zero timeout":     {"address": "x", "timeout": "0s"},
+		"negative timeout": {"address": "x", "timeout": "-5s"},
+	}
+
+	for name, cfg := range tests {
+		if _, err := newSyslogOutput(nil, cfg); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	for network, want := range map[string]string{
+		syslogUnix:     "/dev/log",
+		syslogUnixgram: "/dev/log",
+		syslogJournald: "/run/systemd/journal/socket",
+	} {
+		if so := newTestSyslogOutput(t, map[string]string{"network": network}); so.address != want {
+			t.Errorf("%s: expected default address %q, saw %q", network, want, so.address)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	flagPipe = "pipe"
+
+	keyFormat = "format"
+	keyOut    = "out"
+)
+
+// pipeline renders the diff with a single formatter into a single output
+type pipeline struct {
+	Formatter Formatter
+	Output    Output
+}
+
+// formatAware is implemented by outputs whose behavior depends on the format rendered into them, such as the content
+// type sent by the http output.
+type formatAware interface {
+	setFormat(format string)
+}
+
+// newOutput builds the named output, informing it of the format rendered into it
+func newOutput(cctx *cli.Context, name, format string, cfg map[string]string) (Output, error) {
+	ctor, ok := outputs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown output %q specified, expected to be one of: %v", name, AvailableOutputs())
+	}
+	out, err := ctor(cctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if fa, ok := out.(formatAware); ok {
+		fa.setFormat(format)
+	}
+	return out, nil
+}
+
+// splitPipeFields splits a pipeline spec on whitespace.  Double quotes group text containing whitespace into a single
+// field and are removed, and within them a backslash escapes a double quote or backslash, e.g.
+// `out.title="Schema \"drift\""` is the single field `out.title=Schema "drift"`.
+func splitPipeFields(in string) ([]string, error) {
+	var (
+		out             []string
+		field           strings.Builder
+		inField, quoted bool
+	)
+
+	for i := 0; i < len(in); i++ {
+		c := in[i]
+		switch {
+		case quoted && c == '\\' && i+1 < len(in) && (in[i+1] == '"' || in[i+1] == '\\'):
+			i++
+			field.WriteByte(in[i])
+		case c == '"':
+			quoted, inField = !quoted, true
+		case !quoted && (c == ' ' || c == '\t' || c == '\n' || c == '\r'):
+			if inField {
+				out = append(out, field.String())
+				field.Reset()
+				inField = false
+			}
+		default:
+			field.WriteByte(c)
+			inField = true
+		}
+	}
+
+	if quoted {
+		return nil, errors.New("unterminated quote")
+	}
+	if inField {
+		out = append(out, field.String())
+	}
+
+	return out, nil
+}
+
+// parsePipeConfig parses a pipeline spec with structure "format=$format out=$out[ format.$key=$value][ out.$key=$value]"
+func parsePipeConfig(cctx *cli.Context, in string) (*pipeline, error) {
+	format, out := FormatSimpleTable, OutputStdOut
+	formatCfg, outCfg := make(map[string]string), make(map[string]string)
+
+	fields, err := splitPipeFields(in)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range fields {
+		p := strings.SplitN(s, "=", 2)
+		if len(p) != 2 {
+			return nil, errors.New("pipe format must be $key=$value")
+		}
+
+		key, value := p[0], p[1]
+
+		switch {
+		case key == keyFormat:
+			format = value
+		case key == keyOut:
+			out = value
+		case strings.HasPrefix(key, keyFormat+"."):
+			formatCfg[strings.TrimPrefix(key, keyFormat+".")] = value
+		case strings.HasPrefix(key, keyOut+"."):
+			outCfg[strings.TrimPrefix(key, keyOut+".")] = value
+		default:
+			return nil, fmt.Errorf("unknown key %q", key)
+		}
+	}
+
+	ctor, ok := formatters[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown formatter %q specified, expected one of: %v", format, AvailableFormatters())
+	}
+
+	var pl pipeline
+	if pl.Formatter, err = ctor(cctx, formatCfg); err != nil {
+		return nil, fmt.Errorf("error building formatter: %w", err)
+	}
+	if pl.Output, err = newOutput(cctx, out, format, outCfg); err != nil {
+		return nil, fmt.Errorf("error building output: %w", err)
+	}
+
+	return &pl, nil
+}
+
+// BuildPipelines returns a pipeline for every -pipe flag.  The pipeline described by -format and -out is included if
+// no -pipe flag was provided, or if either of them or their config was set explicitly.
+func BuildPipelines(cctx *cli.Context) ([]*pipeline, error) {
+	out := make([]*pipeline, 0)
+
+	specs := cctx.StringSlice(flagPipe)
+
+	mainSet := cctx.IsSet(flagFormat) || cctx.IsSet(flagOut) || cctx.IsSet(flagFormatConfig) || cctx.IsSet(flagOutConfig)
+	if len(specs) == 0 || mainSet {
+		formatter, err := BuildFormatter(cctx)
+		if err != nil {
+			return nil, fmt.Errorf("error building formatter: %w", err)
+		}
+		output, err := BuildOutput(cctx)
+		if err != nil {
+			return nil, fmt.Errorf("error building output: %w", err)
+		}
+		out = append(out, &pipeline{Formatter: formatter, Output: output})
+	}
+
+	for i, spec := range specs {
+		pl, err := parsePipeConfig(cctx, spec)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing pipe %d: %w", i+1, err)
+		}
+		out = append(out, pl)
+	}
+
+	return out, nil
+}
+
+// Run renders the summaries into the pipeline's output, publishing the result once rendered in full
+func (pl *pipeline) Run(summaries connectionSummaries) error {
+	return renderDatabases(pl.Output, summaries, true, pl.Formatter.Render)
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/urfave/cli/v2"
+)
+
+func TestSplitPipeFields(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"   ", nil},
+		{"format=json out=stdout", []string{"format=json", "out=stdout"}},
+		{"  format=json\tout=stdout  ", []string{"format=json", "out=stdout"}},
+		{`out.title="Schema drift" format=json`, []string{"out.title=Schema drift", "format=json"}},
+		{`"out.title=Schema drift"`, []string{"out.title=Schema drift"}},
+		{`out.title=""`, []string{"out.title="}},
+		{`out.title="say \"hi\""`, []string{`out.title=say "hi"`}},
+		{`out.dest="C:\\reports\\drift.json"`, []string{`out.dest=C:\reports\drift.json`}},
+		{`out.dest=C:\reports`, []string{`out.dest=C:\reports`}},
+		{`out.title="a  b"c`, []string{"out.title=a  bc"}},
+	}
+
+	for _, tt := range tests {
+		got, err := splitPipeFields(tt.in)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.in, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%q: got %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{`out.title="Schema drift`, `"`, `out.title="a\"`} {
+		if _, err := splitPipeFields(in); err == nil {
+			t.Errorf("%q: expected an unterminated quote error", in)
+		}
+	}
+}
+
+func TestParsePipeConfig(t *testing.T) {
+	cctx := cli.NewContext(nil, nil, nil)
+
+	pl, err := parsePipeConfig(cctx, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pl.Formatter.Type() != FormatSimpleTable || pl.Output.Type() != OutputStdOut {
+		t.Errorf("expected the default formatter and output, saw %q and %q", pl.Formatter.Type(), pl.Output.Type())
+	}
+
+	pl, err = parsePipeConfig(cctx, `format=csv format.delimiter=tab out=chat out.url=https://chat.example.com/hooks/abc out.title="Nightly schema drift" out.top=3`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pl.Formatter.Type() != FormatCSV {
+		t.Errorf("expected the %q formatter, saw %q", FormatCSV, pl.Formatter.Type())
+	}
+	co, ok := pl.Output.(*ChatOutput)
+	if !ok {
+		t.Fatalf("expected a chat output, saw %T", pl.Output)
+	}
+	if co.title != "Nightly schema drift" || co.top != 3 {
+		t.Errorf("expected the configured title and top, saw %q and %d", co.title, co.top)
+	}
+
+	tests := map[string]string{
+		"missing value":     "format",
+		"unknown key":       "formatter=json",
+		"unknown format":    "format=yaml",
+		"unknown output":    "out=printer",
+		"invalid format":    "format=csv format.header=maybe",
+		"invalid output":    "out=file",
+		"unterminated":      `format=json out.title="drift`,
+		"unquoted space":    "format=json out=chat out.url=https://chat.example.com out.title=Nightly drift",
+		"empty output name": "out=",
+	}
+
+	for name, in := range tests {
+		if _, err := parsePipeConfig(cctx, in); err == nil {
+			t.Errorf("%s: expected %q to be rejected", name, in)
+		}
+	}
+}
+
+func TestBuildPipelines(t *testing.T) {
+	build := func(t *testing.T, args ...string) []*pipeline {
+		t.Helper()
+
+		var out []*pipeline
+		app := &cli.App{
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: flagFormat, Value: FormatSimpleTable},
+				&MapStringFlag{Name: flagFormatConfig},
+				outFlag("diff"),
+				outConfigFlag(),
+				&cli.StringSliceFlag{Name: flagPipe},
+			},
+			Action: func(cctx *cli.Context) error {
+				var err error
+				out, err = BuildPipelines(cctx)
+				return err
+			},
+		}
+		if err := app.Run(append([]string{"mysql-diff"}, args...)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return out
+	}
+
+	formats := func(pls []*pipeline) []string {
+		out := make([]string, len(pls))
+		for i, pl := range pls {
+			out[i] = pl.Formatter.Type() + ">" + pl.Output.Type()
+		}
+		return out
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"default", nil, []string{"simple-table>stdout"}},
+		{"pipes only", []string{"-pipe", "format=json"}, []string{"json>stdout"}},
+		{"format", []string{"-format", "csv", "-pipe", "format=json"}, []string{"csv>stdout", "json>stdout"}},
+		{"format config", []string{"-format-config", "header=false", "-pipe", "format=json"}, []string{"simple-table>stdout", "json>stdout"}},
+		{"out config", []string{"-out-config", "unused=true", "-pipe", "format=json"}, []string{"simple-table>stdout", "json>stdout"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formats(build(t, tt.args...)); !slices.Equal(got, tt.want) {
+				t.Errorf("got pipelines %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -21,13 +21,28 @@ type mysqlConn struct {
 	Databases []string
 }
 
+var _ summarySource = (*mysqlConn)(nil)
+
+func (mc *mysqlConn) Summarize(ctx context.Context) (connectionSummaries, error) {
+	cs, err := summarizeConnection(ctx, mc)
+	if err != nil {
+		return nil, err
+	}
+	return connectionSummaries{cs}, nil
+}
+
+func (mc *mysqlConn) Close() error {
+	if mc.Conn != nil {
+		return mc.Conn.Close()
+	}
+	return nil
+}
+
 type mysqlConns []*mysqlConn
 
 func (mcs mysqlConns) Close() {
 	for _, mc := range mcs {
-		if mc.Conn != nil {
-			_ = mc.Conn.Close()
-		}
+		_ = mc.Close()
 	}
 }
 
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// snapshotVersion is the version of the snapshot envelope written by this build.  It must be incremented, and a
+// reader for the previous version retained in decodeSnapshot, whenever a change to the summary structs would prevent
+// older snapshots from decoding correctly.
+const snapshotVersion = 1
+
+var _ summarySource = (*snapshotSource)(nil)
+
+// snapshotFile is the self-describing envelope written by the summary command
+type snapshotFile struct {
+	Version     int                 `json:"version"`
+	CapturedAt  time.Time           `json:"captured_at"`
+	ToolVersion string              `json:"tool_version"`
+	Connections connectionSummaries `json:"connections"`
+}
+
+func newSnapshotFile(summaries connectionSummaries) *snapshotFile {
+	return &snapshotFile{
+		Version:     snapshotVersion,
+		CapturedAt:  time.Now().UTC(),
+		ToolVersion: toolVersion(),
+		Connections: summaries,
+	}
+}
+
+// decodeSnapshot decodes any known version of the snapshot format.  Version 0 is the bare list of connection
+// summaries written before the envelope was introduced.
+func decodeSnapshot(b []byte) (*snapshotFile, error) {
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 {
+		return nil, errors.New("snapshot is empty")
+	}
+
+	if b[0] == '[' {
+		snap := snapshotFile{Version: 0}
+		if err := json.Unmarshal(b, &snap.Connections); err != nil {
+			return nil, fmt.Errorf("error decoding version 0 snapshot: %w", err)
+		}
+		return &snap, nil
+	}
+
+	var probe struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(b, &probe); err != nil {
+		return nil, fmt.Errorf("error decoding snapshot version: %w", err)
+	}
+
+	switch probe.Version {
+	case 1:
+		var snap snapshotFile
+		if err := json.Unmarshal(b, &snap); err != nil {
+			return nil, fmt.Errorf("error decoding version %d snapshot: %w", probe.Version, err)
+		}
+		return &snap, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported snapshot version %d, this build supports up to version %d", probe.Version, snapshotVersion)
+	}
+}
+
+// snapshotSource reads connection summaries from a file previously written by the summary command
+type snapshotSource struct {
+	Label string
+	Path  string
+}
+
+func parseSnapshotConfig(in string) (*snapshotSource, error) {
+	in = strings.TrimSpace(in)
+	if in == "" {
+		return nil, errors.New("snapshot must not be empty")
+	}
+
+	ss := snapshotSource{Path: in}
+
+	// an existing file is never split, so that unlabeled paths may contain "="
+	if _, err := os.Stat(in); err != nil {
+		if idx := strings.Index(in, "="); idx != -1 {
+			ss.Label, ss.Path = strings.TrimSpace(in[:idx]), strings.TrimSpace(in[idx+1:])
+		}
+	}
+
+	if ss.Path == "" {
+		return nil, errors.New("snapshot path must not be empty")
+	}
+
+	return &ss, nil
+}
+
+func parseSnapshotFlags(cctx *cli.Context) ([]*snapshotSource, error) {
+	var out []*snapshotSource
+
+	for _, sf := range cctx.StringSlice(flagSnapshot) {
+		ss, err := parseSnapshotConfig(sf)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing snapshot config: %w", err)
+		}
+		out = append(out, ss)
+	}
+
+	return out, nil
+}
+
+// snapshotJSON returns the JSON encoding of a snapshot written with the yaml or toml summary formatter, identified by
+// the extension of its path.  Snapshots with any other extension are returned unchanged.
+func snapshotJSON(path string, b []byte) ([]byte, error) {
+	var (
+		doc any
+		err error
+	)
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, &doc)
+	case ".toml":
+		err = toml.Unmarshal(b, &doc)
+	default:
+		return b, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(doc)
+}
+
+// Summarize reads the snapshot file.  If a label was provided it replaces the label of the stored connection, or is
+// used as a prefix when the snapshot contains more than one connection.
+func (ss *snapshotSource) Summarize(_ context.Context) (connectionSummaries, error) {
+	b, err := os.ReadFile(ss.Path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading snapshot %q: %w", ss.Path, err)
+	}
+
+	if b, err = snapshotJSON(ss.Path, b); err != nil {
+		return nil, fmt.Errorf("error decoding snapshot %q: %w", ss.Path, err)
+	}
+
+	snap, err := decodeSnapshot(b)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding snapshot %q: %w", ss.Path, err)
+	}
+
+	summaries := snap.Connections
+
+	if ss.Label != "" {
+		for _, cs := range summaries {
+			if len(summaries) == 1 {
+				cs.Label = ss.Label
+			} else {
+				cs.Label = fmt.Sprintf("%s:%s", ss.Label, cs.DisplayName())
+			}
+		}
+	}
+
+	return summaries, nil
+}
+
+func (*snapshotSource) Close() error {
+	return nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestParseSnapshotConfig(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "run=1.json")
+	if err := os.WriteFile(existing, []byte("[]"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		in    string
+		label string
+		path  string
+		err   bool
+	}{
+		{in: "summary.json", path: "summary.json"},
+		{in: "last-week=summary.json", label: "last-week", path: "summary.json"},
+		{in: " prod = a/b.json ", label: "prod", path: "a/b.json"},
+		{in: existing, path: existing},
+		{in: "old=" + existing, label: "old", path: existing},
+		{in: "", err: true},
+		{in: "label=", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			ss, err := parseSnapshotConfig(tt.in)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", ss)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ss.Label != tt.label || ss.Path != tt.path {
+				t.Errorf("expected label %q and path %q, got %q and %q", tt.label, tt.path, ss.Label, ss.Path)
+			}
+		})
+	}
+}
+
+func TestDecodeSnapshot(t *testing.T) {
+	legacy, err := os.ReadFile(filepath.Join("testdata", "snapshot", "v0.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snap, err := decodeSnapshot(legacy)
+	if err != nil {
+		t.Fatalf("error decoding version 0 snapshot: %v", err)
+	}
+	if snap.Version != 0 || len(snap.Connections) != 1 || snap.Connections[0].Label != "prod" {
+		t.Fatalf("unexpected version 0 snapshot %+v", snap)
+	}
+
+	tbl := snap.Connections[0].Databases[0].Tables[0]
+	if tbl.Columns[0].Default != nil {
+		t.Errorf("expected invalid legacy default to decode as NULL, got %q", *tbl.Columns[0].Default)
+	}
+	if d := tbl.Columns[1].Default; d == nil || *d != "active" {
+		t.Errorf("expected valid legacy default to decode as \"active\", got %v", d)
+	}
+	if tbl.Indexes != nil || tbl.ForeignKeys != nil {
+		t.Error("expected indexes and foreign keys of a legacy snapshot to remain unknown")
+	}
+
+	// a snapshot written by this build decodes to the same summaries
+	b, err := json.Marshal(newSnapshotFile(snap.Connections))
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, err := decodeSnapshot(b)
+	if err != nil {
+		t.Fatalf("error decoding version %d snapshot: %v", snapshotVersion, err)
+	}
+	if current.Version != snapshotVersion || current.ToolVersion == "" || current.CapturedAt.IsZero() {
+		t.Errorf("unexpected snapshot envelope %+v", current)
+	}
+	if !reflect.DeepEqual(current.Connections, snap.Connections) {
+		t.Error("expected summaries to survive encoding and decoding unchanged")
+	}
+
+	for name, in := range map[string]string{
+		"empty":       " \n",
+		"unsupported": `{"version": 99, "connections": []}`,
+		"invalid":     `{"version": "1"}`,
+	} {
+		if _, err := decodeSnapshot([]byte(in)); err == nil {
+			t.Errorf("expected %s snapshot to be rejected", name)
+		}
+	}
+}
+
+func TestSnapshotSourceLabels(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "two.json")
+	b, err := json.Marshal(newSnapshotFile(connectionSummaries{{Label: "a"}, {Address: "b:3306"}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(path, b, 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		label string
+		want  []string
+	}{
+		{"", []string{"a", "b:3306"}},
+		{"old", []string{"old:a", "old:b:3306"}},
+	}
+
+	for _, tt := range tests {
+		summaries, err := (&snapshotSource{Label: tt.label, Path: path}).Summarize(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := []string{summaries[0].DisplayName(), summaries[1].DisplayName()}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("expected label %q to name connections %v, got %v", tt.label, tt.want, got)
+		}
+	}
+
+	single, err := (&snapshotSource{Label: "old", Path: filepath.Join("testdata", "snapshot", "v0.json")}).Summarize(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if single[0].Label != "old" {
+		t.Errorf("expected label to replace the label of a single connection, got %q", single[0].Label)
+	}
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"context"
+)
+
+const (
+	sourceMySQL = "mysql"
+)
+
+// summarySource is anything capable of producing one or more connection summaries, e.g. a live MySQL connection or a
+// previously stored summary file.
+type summarySource interface {
+	Summarize(context.Context) (connectionSummaries, error)
+	Close() error
+}
+
+type summarySources []summarySource
+
+func (ss summarySources) Close() {
+	for _, src := range ss {
+		_ = src.Close()
+	}
+}
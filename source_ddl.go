@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	sourceDDL        = "ddl"
+	sourceMigrations = "migrations"
+
+	keyPath = "path"
+)
+
+var _ summarySource = (*ddlSource)(nil)
+
+// ddlSource builds summaries by parsing CREATE statements from a SQL file, or from every .sql file within a directory,
+// without connecting to a server.  If Migrations is true, Path is instead read as a directory of versioned migrations
+// which are replayed in order.
+type ddlSource struct {
+	Label      string
+	Path       string
+	Databases  []string
+	Migrations bool
+}
+
+func parseDDLConfig(in string) (*ddlSource, error) {
+	var ds ddlSource
+
+	for _, s := range strings.Split(in, " ") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+
+		p := strings.SplitN(s, "=", 2)
+		if len(p) != 2 {
+			return nil, errors.New("ddl source format must be $key=$value")
+		}
+
+		key, value := strings.TrimSpace(p[0]), strings.TrimSpace(p[1])
+		if value == "" {
+			continue
+		}
+
+		switch key {
+		case keyLabel:
+			if ds.Label != "" {
+				return nil, fmt.Errorf("each ddl source must have only one %q key", keyLabel)
+			}
+			ds.Label = value
+		case keyPath:
+			if ds.Path != "" {
+				return nil, fmt.Errorf("each ddl source must have only one %q key", keyPath)
+			}
+			ds.Path = value
+		case keyDB:
+			ds.Databases = append(ds.Databases, value)
+
+		default:
+			return nil, fmt.Errorf("unknown key %q", key)
+		}
+	}
+
+	if ds.Path == "" {
+		return nil, errors.New("path must not be empty")
+	}
+
+	return &ds, nil
+}
+
+func parseDDLFlags(cctx *cli.Context) ([]*ddlSource, error) {
+	var out []*ddlSource
+
+	for _, df := range cctx.StringSlice(flagDDL) {
+		ds, err := parseDDLConfig(df)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing ddl source config: %w", err)
+		}
+		out = append(out, ds)
+	}
+
+	for _, mf := range cctx.StringSlice(flagMigrations) {
+		ds, err := parseDDLConfig(mf)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing migrations source config: %w", err)
+		}
+		ds.Migrations = true
+		out = append(out, ds)
+	}
+
+	return out, nil
+}
+
+// sqlFiles returns the provided path if it is a file, or every .sql file beneath it in lexical order if it is a
+// directory.
+func sqlFiles(path string) ([]string, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !fi.IsDir() {
+		return []string{path}, nil
+	}
+
+	out := make([]string, 0)
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.EqualFold(filepath.Ext(p), ".sql") {
+			out = append(out, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Sort(out)
+
+	return out, nil
+}
+
+// defaultDatabase returns the database targeted by statements preceding any USE statement
+func (ds *ddlSource) defaultDatabase() string {
+	if len(ds.Databases) > 0 {
+		return ds.Databases[0]
+	}
+	base := filepath.Base(ds.Path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// statements returns every statement of every file in the source, in the order they must be applied
+func (ds *ddlSource) statements() ([]ddlStatement, error) {
+	if ds.Migrations {
+		return migrationStatements(ds.Path)
+	}
+
+	files, err := sqlFiles(ds.Path)
+	if err != nil {
+		return nil, fmt.Errorf("error listing ddl files in %q: %w", ds.Path, err)
+	}
+
+	out := make([]ddlStatement, 0)
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("error reading ddl file %q: %w", f, err)
+		}
+
+		stmts, err := splitDDL(f, string(b))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing ddl file: %w", err)
+		}
+
+		out = append(out, stmts...)
+	}
+
+	return out, nil
+}
+
+func (ds *ddlSource) Summarize(_ context.Context) (connectionSummaries, error) {
+	stmts, err := ds.statements()
+	if err != nil {
+		return nil, err
+	}
+
+	schema := newDDLSchema(ds.defaultDatabase())
+	parser := &ddlParser{schema: schema}
+
+	for _, stmt := range stmts {
+		if err = parser.Apply(stmt); err != nil {
+			return nil, fmt.Errorf("error applying ddl statement: %w", err)
+		}
+	}
+
+	// databases which were never used by any statement are omitted unless explicitly requested
+	if len(ds.Databases) == 0 {
+		schema.Databases = slices.DeleteFunc(schema.Databases, func(d *ddlDatabase) bool {
+			return len(d.Tables) == 0 && len(d.Routines) == 0 && len(d.Triggers) == 0
+		})
+	}
+
+	dbs, err := schema.Summaries(ds.Databases)
+	if err != nil {
+		return nil, fmt.Errorf("error summarizing ddl source %q: %w", ds.Path, err)
+	}
+
+	cs := &connectionSummary{
+		Label:     ds.Label,
+		Address:   ds.Path,
+		Source:    sourceDDL,
+		Databases: dbs,
+	}
+
+	if ds.Migrations {
+		cs.Source = sourceMigrations
+	}
+
+	return connectionSummaries{cs}, nil
+}
+
+func (*ddlSource) Close() error {
+	return nil
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	// golang-migrate: 1_create_users.up.sql, 1_create_users.down.sql
+	migrateFilePattern = regexp.MustCompile(`^(\d+)_(.*)\.(up|down)\.sql$`)
+	// Flyway: V1.2__create_users.sql, U1.2__create_users.sql, R__users_view.sql
+	flywayFilePattern = regexp.MustCompile(`^([VUR])(\d+(?:[._]\d+)*)?__(.*)\.sql$`)
+	// goose: 20240101120000_create_users.sql
+	gooseFilePattern = regexp.MustCompile(`^(\d+)_(.*)\.sql$`)
+
+	gooseAnnotationPattern    = regexp.MustCompile(`^--\s*\+goose\s+(\w+)`)
+	liquibaseHeaderPattern    = regexp.MustCompile(`(?i)^--\s*liquibase\s+formatted\s+sql`)
+	liquibaseChangesetPattern = regexp.MustCompile(`(?i)^--\s*changeset\s+`)
+)
+
+// migrationFile is a single up-migration found within a migrations directory
+type migrationFile struct {
+	Path        string
+	Version     []uint64
+	Repeatable  bool
+	Description string
+}
+
+// order returns the group a migration is applied in: versioned migrations first, then unversioned files such as
+// Liquibase changelogs, and finally Flyway repeatable migrations.
+func (mf migrationFile) order() int {
+	switch {
+	case mf.Repeatable:
+		return 2
+	case mf.Version == nil:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareMigrations(a, b migrationFile) int {
+	if c := cmp.Compare(a.order(), b.order()); c != 0 {
+		return c
+	}
+	if c := slices.Compare(a.Version, b.Version); c != 0 {
+		return c
+	}
+	return strings.Compare(a.Description, b.Description)
+}
+
+func parseMigrationVersion(in string) ([]uint64, error) {
+	out := make([]uint64, 0)
+	for _, s := range strings.FieldsFunc(in, func(r rune) bool { return r == '.' || r == '_' }) {
+		v, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version %q: %w", in, err)
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+// listMigrations returns the up-migrations within the provided directory, in the order they must be applied.  Down
+// and undo migrations, and files which are neither versioned nor Liquibase changelogs, are ignored.
+func listMigrations(dir string) ([]migrationFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading migrations directory %q: %w", dir, err)
+	}
+
+	out := make([]migrationFile, 0)
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.EqualFold(filepath.Ext(name), ".sql") {
+			continue
+		}
+
+		mf := migrationFile{Path: filepath.Join(dir, name), Description: name}
+
+		var version string
+		switch {
+		case migrateFilePattern.MatchString(name):
+			m := migrateFilePattern.FindStringSubmatch(name)
+			if m[3] == "down" {
+				continue
+			}
+			version = m[1]
+
+		case flywayFilePattern.MatchString(name):
+			m := flywayFilePattern.FindStringSubmatch(name)
+			switch m[1] {
+			case "U":
+				continue
+			case "R":
+				mf.Repeatable, mf.Description = true, m[3]
+			default:
+				if m[2] == "" {
+					return nil, fmt.Errorf("flyway migration %q has no version", name)
+				}
+				version = m[2]
+			}
+
+		case gooseFilePattern.MatchString(name):
+			version = gooseFilePattern.FindStringSubmatch(name)[1]
+
+		default:
+			b, err := os.ReadFile(mf.Path)
+			if err != nil {
+				return nil, fmt.Errorf("error reading migration %q: %w", mf.Path, err)
+			}
+			if !liquibaseHeaderPattern.MatchString(strings.TrimSpace(string(b))) {
+				continue
+			}
+		}
+
+		if version != "" {
+			if mf.Version, err = parseMigrationVersion(version); err != nil {
+				return nil, fmt.Errorf("error parsing migration %q: %w", name, err)
+			}
+		}
+
+		out = append(out, mf)
+	}
+
+	slices.SortFunc(out, compareMigrations)
+
+	for i := 1; i < len(out); i++ {
+		if out[i].Version != nil && slices.Equal(out[i].Version, out[i-1].Version) {
+			return nil, fmt.Errorf("migrations %q and %q share the same version", out[i-1].Path, out[i].Path)
+		}
+	}
+
+	return out, nil
+}
+
+// migrationStatements returns every up-migration statement within the provided directory, in application order
+func migrationStatements(dir string) ([]ddlStatement, error) {
+	files, err := listMigrations(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]ddlStatement, 0)
+	for _, mf := range files {
+		b, err := os.ReadFile(mf.Path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading migration %q: %w", mf.Path, err)
+		}
+
+		stmts, err := splitMigration(mf.Path, string(b))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing migration: %w", err)
+		}
+
+		out = append(out, stmts...)
+	}
+
+	return out, nil
+}
+
+// migrationChunk is a range of lines within a migration file which is lexed with a single initial delimiter.  An
+// empty delimiter means the entire chunk is one statement.
+type migrationChunk struct {
+	start int
+	lines []string
+	delim string
+}
+
+func (mc migrationChunk) statements(file string) ([]ddlStatement, error) {
+	// pad with empty lines so reported line numbers match the file
+	src := strings.Repeat("\n", mc.start) + strings.Join(mc.lines, "\n")
+	if mc.delim == "" {
+		src = strings.TrimSuffix(strings.TrimRightFunc(src, func(r rune) bool { return r == ' ' || r == '\t' || r == '\r' || r == '\n' }), ";")
+	}
+	return splitDDLWithDelimiter(file, src, mc.delim)
+}
+
+// splitMigration splits a single migration file into statements, honoring goose and Liquibase annotations
+func splitMigration(file, src string) ([]ddlStatement, error) {
+	lines := strings.Split(src, "\n")
+
+	var chunks []migrationChunk
+	switch {
+	case liquibaseHeaderPattern.MatchString(strings.TrimSpace(src)):
+		chunks = liquibaseChunks(lines)
+	case slices.ContainsFunc(lines, func(l string) bool { return gooseAnnotationPattern.MatchString(strings.TrimSpace(l)) }):
+		chunks = gooseChunks(lines)
+	default:
+		return splitDDL(file, src)
+	}
+
+	out := make([]ddlStatement, 0)
+	for _, c := range chunks {
+		stmts, err := c.statements(file)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, stmts...)
+	}
+
+	return out, nil
+}
+
+// gooseChunks returns the chunks of the "Up" section of a goose migration.  Statements wrapped in StatementBegin and
+// StatementEnd annotations are returned as single statements.
+func gooseChunks(lines []string) []migrationChunk {
+	var (
+		out   []migrationChunk
+		cur   = migrationChunk{delim: ";"}
+		up    bool
+		block bool
+	)
+
+	flush := func(next migrationChunk) {
+		if len(cur.lines) > 0 {
+			out = append(out, cur)
+		}
+		cur = next
+	}
+
+	for i, l := range lines {
+		m := gooseAnnotationPattern.FindStringSubmatch(strings.TrimSpace(l))
+		if m == nil {
+			if !up {
+				l = ""
+			}
+			if len(cur.lines) == 0 {
+				cur.start = i
+			}
+			cur.lines = append(cur.lines, l)
+			continue
+		}
+
+		switch strings.ToLower(m[1]) {
+		case "up":
+			up = true
+		case "down":
+			up = false
+		case "statementbegin":
+			block = true
+			flush(migrationChunk{start: i + 1})
+		case "statementend":
+			if block {
+				block = false
+				flush(migrationChunk{start: i + 1, delim: ";"})
+			}
+		}
+	}
+
+	flush(migrationChunk{})
+
+	return out
+}
+
+// liquibaseChunks returns one chunk per changeset of a Liquibase formatted SQL changelog.  The endDelimiter and
+// splitStatements changeset attributes are honored, and all other annotations, including rollback statements, are
+// ignored.
+func liquibaseChunks(lines []string) []migrationChunk {
+	var (
+		out []migrationChunk
+		cur *migrationChunk
+	)
+
+	for i, l := range lines {
+		trimmed := strings.TrimSpace(l)
+
+		if liquibaseChangesetPattern.MatchString(trimmed) {
+			if cur != nil {
+				out = append(out, *cur)
+			}
+			cur = &migrationChunk{start: i + 1, delim: ";"}
+			for _, attr := range strings.Fields(trimmed)[2:] {
+				k, v, _ := strings.Cut(attr, ":")
+				switch strings.ToLower(k) {
+				case "enddelimiter":
+					cur.delim = strings.ReplaceAll(v, `\`, "")
+				case "splitstatements":
+					if b, err := strconv.ParseBool(v); err == nil && !b {
+						cur.delim = ""
+					}
+				}
+			}
+			continue
+		}
+
+		if cur == nil {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "--") {
+			l = ""
+		}
+		cur.lines = append(cur.lines, l)
+	}
+
+	if cur != nil {
+		out = append(out, *cur)
+	}
+
+	return out
+}
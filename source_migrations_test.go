@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writeMigrations writes the provided files to a temporary directory, returning its path
+func writeMigrations(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// replayMigrations summarizes the database "db" after replaying the provided migrations
+func replayMigrations(t *testing.T, files map[string]string) (*databaseSummary, error) {
+	t.Helper()
+
+	ds := &ddlSource{Path: writeMigrations(t, files), Databases: []string{"db"}, Migrations: true}
+	summaries, err := ds.Summarize(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return summaries[0].Databases[0], nil
+}
+
+func TestListMigrations(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []string
+	}{
+		{
+			name: "golang-migrate",
+			files: map[string]string{
+				"10_c.up.sql":  "",
+				"2_b.up.sql":   "",
+				"2_b.down.sql": "",
+				"1_a.up.sql":   "",
+				"README.md":    "",
+			},
+			want: []string{"1_a.up.sql", "2_b.up.sql", "10_c.up.sql"},
+		},
+		{
+			name: "flyway",
+			files: map[string]string{
+				"V1.10__c.sql":   "",
+				"V1.2__b.sql":    "",
+				"V1__a.sql":      "",
+				"U1.2__b.sql":    "",
+				"R__views.sql":   "",
+				"R__a_views.sql": "",
+			},
+			want: []string{"V1__a.sql", "V1.2__b.sql", "V1.10__c.sql", "R__a_views.sql", "R__views.sql"},
+		},
+		{
+			name: "goose and liquibase",
+			files: map[string]string{
+				"changelog.sql":             "-- liquibase formatted sql\n",
+				"notes.sql":                 "SELECT 1;",
+				"20240102000000_second.sql": "",
+				"20240101000000_first.sql":  "",
+			},
+			want: []string{"20240101000000_first.sql", "20240102000000_second.sql", "changelog.sql"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := listMigrations(writeMigrations(t, tt.files))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := make([]string, len(files))
+			for i, f := range files {
+				got[i] = filepath.Base(f.Path)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected order %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := listMigrations(writeMigrations(t, map[string]string{"V__a.sql": ""})); err == nil {
+		t.Error("expected a versioned flyway migration without a version to be rejected")
+	}
+}
+
+func TestSplitMigration(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []string
+	}{
+		{
+			name: "plain",
+			src:  "CREATE TABLE a (id int);\nCREATE TABLE b (id int);",
+			want: []string{"CREATE TABLE a (id int)", "CREATE TABLE b (id int)"},
+		},
+		{
+			name: "goose",
+			src: `-- +goose Up
+CREATE TABLE a (id int);
+-- +goose StatementBegin
+CREATE PROCEDURE p() BEGIN SELECT 1; SELECT 2; END;
+-- +goose StatementEnd
+-- +goose Down
+DROP TABLE a;`,
+			want: []string{"CREATE TABLE a (id int)", "CREATE PROCEDURE p() BEGIN SELECT 1; SELECT 2; END"},
+		},
+		{
+			name: "liquibase",
+			src: `-- liquibase formatted sql
+-- changeset alice:1
+CREATE TABLE a (id int);
+-- rollback DROP TABLE a;
+-- changeset alice:2 endDelimiter:\$\$
+CREATE PROCEDURE p() BEGIN SELECT 1; END$$
+-- changeset alice:3 splitStatements:false
+CREATE FUNCTION f() RETURNS int RETURN 1;`,
+			want: []string{"CREATE TABLE a (id int)", "CREATE PROCEDURE p() BEGIN SELECT 1; END", "CREATE FUNCTION f() RETURNS int RETURN 1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmts, err := splitMigration("m.sql", tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := make([]string, len(stmts))
+			for i, s := range stmts {
+				got[i] = s.Text(0, len(s.Tokens)-1)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected statements:\n%q\ngot:\n%q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestReplayMigrations(t *testing.T) {
+	ds, err := replayMigrations(t, map[string]string{
+		"1_init.up.sql": `
+CREATE TABLE users (id int NOT NULL AUTO_INCREMENT PRIMARY KEY, name varchar(50), legacy int);
+CREATE TABLE posts (
+	id int PRIMARY KEY,
+	author int NOT NULL,
+	CONSTRAINT fk_author FOREIGN KEY (author) REFERENCES users (id)
+);
+CREATE TRIGGER users_bi BEFORE INSERT ON users FOR EACH ROW SET NEW.name = TRIM(NEW.name);
+CREATE INDEX idx_name ON users (name);`,
+		"1_init.down.sql": "DROP TABLE posts; DROP TABLE users;",
+		"2_alter.up.sql": `
+ALTER TABLE users
+	MODIFY name varchar(100) NOT NULL,
+	ADD COLUMN email varchar(255) AFTER id,
+	ADD UNIQUE KEY uq_email (email),
+	DROP COLUMN legacy;
+ALTER TABLE users RENAME COLUMN id TO user_id;
+RENAME TABLE users TO accounts;
+ALTER TABLE posts CHANGE author author_id int NOT NULL;
+DROP INDEX idx_name ON accounts;`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := ds.TableNames(); !slices.Equal(got, []string{"posts", "accounts"}) {
+		t.Errorf("unexpected tables %v", got)
+	}
+
+	accounts, _ := ds.FindTable("accounts")
+	if got, want := tableShape(accounts), []string{
+		"column|user_id|int|NO|PRI|NULL|auto_increment",
+		"column|email|varchar(255)|YES|UNI|NULL|",
+		"column|name|varchar(100)|NO||NULL|",
+		"index|PRIMARY|PRIMARY KEY (user_id)",
+		"index|uq_email|UNIQUE KEY (email)",
+	}; !slices.Equal(got, want) {
+		t.Errorf("expected accounts:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+
+	// the renamed column and table are followed by the foreign key referencing them, and the renamed column by its
+	// implicit index
+	posts, _ := ds.FindTable("posts")
+	if got, want := tableShape(posts), []string{
+		"column|id|int|NO|PRI|NULL|",
+		"column|author_id|int|NO|MUL|NULL|",
+		"index|PRIMARY|PRIMARY KEY (id)",
+		"index|fk_author|KEY (author_id)",
+		"fk|fk_author|FOREIGN KEY (author_id) REFERENCES accounts(user_id)",
+	}; !slices.Equal(got, want) {
+		t.Errorf("expected posts:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+
+	if tr, ok := ds.FindTrigger("users_bi"); !ok || tr.Table != "accounts" {
+		t.Errorf("expected trigger to follow the renamed table, got %+v", tr)
+	}
+
+	if origin := accounts.Columns[1].Origin; origin == nil || filepath.Base(origin.File) != "2_alter.up.sql" || origin.Line != 4 {
+		t.Errorf("expected added column to originate from 2_alter.up.sql:4, got %v", origin)
+	}
+}
+
+func TestReplayMigrationsErrors(t *testing.T) {
+	for name, src := range map[string]string{
+		"missing table":  "ALTER TABLE missing ADD COLUMN a int;",
+		"missing column": "CREATE TABLE a (id int);\nALTER TABLE a DROP COLUMN b;",
+		"missing index":  "CREATE TABLE a (id int);\nDROP INDEX b ON a;",
+		"existing table": "CREATE TABLE a (id int);\nRENAME TABLE a TO a;",
+	} {
+		_, err := replayMigrations(t, map[string]string{"1_a.up.sql": src})
+		if err == nil {
+			t.Errorf("expected %s to stop the replay", name)
+		} else if !strings.Contains(err.Error(), "1_a.up.sql:") {
+			t.Errorf("expected %s error to name the migration, got %q", name, err)
+		}
+	}
+}
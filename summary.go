@@ -1,25 +1,181 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"slices"
+	"strings"
 )
 
+// sourceLocation is the position within a SQL file at which an object was last defined
+type sourceLocation struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+func (sl sourceLocation) String() string {
+	return fmt.Sprintf("%s:%d", sl.File, sl.Line)
+}
+
 type columnSummary struct {
-	Name     string         `json:"name"`
-	Type     string         `json:"type"`
-	Nullable string         `json:"nullable"`
-	Key      string         `json:"key"`
-	Default  sql.NullString `json:"default"`
-	Extra    string         `json:"extra"`
+	Name     string          `json:"name"`
+	Type     string          `json:"type"`
+	Nullable string          `json:"nullable"`
+	Key      string          `json:"key"`
+	Default  *string         `json:"default"`
+	Extra    string          `json:"extra"`
+	Origin   *sourceLocation `json:"origin,omitempty"`
 }
 
-type tableSummary struct {
+// UnmarshalJSON accepts the column default as a string, null, or as the {"String":"","Valid":false} object written by
+// snapshots predating the versioned snapshot format.
+func (cs *columnSummary) UnmarshalJSON(b []byte) error {
+	type plainColumnSummary columnSummary
+
+	var raw struct {
+		plainColumnSummary
+		Default json.RawMessage `json:"default"`
+	}
+
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	*cs = columnSummary(raw.plainColumnSummary)
+	cs.Default = nil
+
+	def := bytes.TrimSpace(raw.Default)
+	switch {
+	case len(def) == 0 || bytes.Equal(def, []byte("null")):
+	case def[0] == '{':
+		var legacy sql.NullString
+		if err := json.Unmarshal(def, &legacy); err != nil {
+			return fmt.Errorf("error decoding column %q default: %w", cs.Name, err)
+		}
+		if legacy.Valid {
+			cs.Default = &legacy.String
+		}
+	default:
+		var v string
+		if err := json.Unmarshal(def, &v); err != nil {
+			return fmt.Errorf("error decoding column %q default: %w", cs.Name, err)
+		}
+		cs.Default = &v
+	}
+
+	return nil
+}
+
+type indexSummary struct {
 	Name    string          `json:"name"`
-	Type    string          `json:"type"`
-	Columns []columnSummary `json:"columns"`
+	Unique  bool            `json:"unique"`
+	Columns []string        `json:"columns"`
+	Origin  *sourceLocation `json:"origin,omitempty"`
+}
+
+// indexProperties lists the compared properties of an index, in display order
+var indexProperties = []string{"unique", "columns"}
+
+// Properties returns the comparable properties of this index, keyed by name
+func (is indexSummary) Properties() map[string]string {
+	unique := "NO"
+	if is.Unique {
+		unique = "YES"
+	}
+	return map[string]string{
+		"unique":  unique,
+		"columns": strings.Join(is.Columns, ","),
+	}
+}
+
+// Description returns a short description of the index, e.g. "UNIQUE KEY (a,b)"
+func (is indexSummary) Description() string {
+	kind := "KEY"
+	switch {
+	case is.Name == "PRIMARY":
+		kind = "PRIMARY KEY"
+	case is.Unique:
+		kind = "UNIQUE KEY"
+	}
+	return fmt.Sprintf("%s (%s)", kind, strings.Join(is.Columns, ","))
+}
+
+type foreignKeySummary struct {
+	Name       string          `json:"name"`
+	Columns    []string        `json:"columns"`
+	RefTable   string          `json:"ref_table"`
+	RefColumns []string        `json:"ref_columns"`
+	Origin     *sourceLocation `json:"origin,omitempty"`
+}
+
+// foreignKeyProperties lists the compared properties of a foreign key, in display order
+var foreignKeyProperties = []string{"columns", "references"}
+
+// Properties returns the comparable properties of this foreign key, keyed by name
+func (fk foreignKeySummary) Properties() map[string]string {
+	return map[string]string{
+		"columns":    strings.Join(fk.Columns, ","),
+		"references": fmt.Sprintf("%s(%s)", fk.RefTable, strings.Join(fk.RefColumns, ",")),
+	}
+}
+
+// Description returns a short description of the foreign key, e.g. "FOREIGN KEY (a) REFERENCES t(b)"
+func (fk foreignKeySummary) Description() string {
+	return fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s(%s)", strings.Join(fk.Columns, ","), fk.RefTable, strings.Join(fk.RefColumns, ","))
+}
+
+// tableSummary describes a table or view.  Indexes and ForeignKeys are nil for summaries read from snapshots
+// predating their support, in which case they are not compared.  Verbatim is set for views read from DDL, whose
+// definition is the statement text as written rather than the expanded form reported by MySQL.
+type tableSummary struct {
+	Name        string              `json:"name"`
+	Type        string              `json:"type"`
+	Definition  string              `json:"definition,omitempty"`
+	Verbatim    bool                `json:"verbatim,omitempty"`
+	Columns     []columnSummary     `json:"columns"`
+	Indexes     []indexSummary      `json:"indexes"`
+	ForeignKeys []foreignKeySummary `json:"foreign_keys"`
+	Origin      *sourceLocation     `json:"origin,omitempty"`
+}
+
+// tableProperties lists the compared properties of a table, in display order
+var tableProperties = []string{"type", "definition"}
+
+// Properties returns the comparable properties of this table, keyed by name
+func (ts tableSummary) Properties() map[string]string {
+	return map[string]string{
+		"type":       ts.Type,
+		"definition": ts.Definition,
+	}
+}
+
+// columnProperties lists the compared properties of a column, in display order
+var columnProperties = []string{"type", "nullable", "key", "default", "extra"}
+
+// Properties returns the comparable properties of this column, keyed by name
+func (cs columnSummary) Properties() map[string]string {
+	def := "NULL"
+	if cs.Default != nil {
+		def = *cs.Default
+	}
+	return map[string]string{
+		"type":     cs.Type,
+		"nullable": cs.Nullable,
+		"key":      cs.Key,
+		"default":  def,
+		"extra":    cs.Extra,
+	}
+}
+
+func (ts tableSummary) ColumnNames() []string {
+	out := make([]string, 0)
+	for _, c := range ts.Columns {
+		out = append(out, c.Name)
+	}
+	return out
 }
 
 func (ts tableSummary) FindColumn(name string) (columnSummary, bool) {
@@ -31,9 +187,94 @@ func (ts tableSummary) FindColumn(name string) (columnSummary, bool) {
 	return columnSummary{}, false
 }
 
+func (ts tableSummary) IndexNames() []string {
+	out := make([]string, 0)
+	for _, idx := range ts.Indexes {
+		out = append(out, idx.Name)
+	}
+	return out
+}
+
+func (ts tableSummary) FindIndex(name string) (indexSummary, bool) {
+	for _, idx := range ts.Indexes {
+		if idx.Name == name {
+			return idx, true
+		}
+	}
+	return indexSummary{}, false
+}
+
+func (ts tableSummary) ForeignKeyNames() []string {
+	out := make([]string, 0)
+	for _, fk := range ts.ForeignKeys {
+		out = append(out, fk.Name)
+	}
+	return out
+}
+
+func (ts tableSummary) FindForeignKey(name string) (foreignKeySummary, bool) {
+	for _, fk := range ts.ForeignKeys {
+		if fk.Name == name {
+			return fk, true
+		}
+	}
+	return foreignKeySummary{}, false
+}
+
+type routineSummary struct {
+	Name          string          `json:"name"`
+	Type          string          `json:"type"`
+	Parameters    string          `json:"parameters"`
+	Returns       string          `json:"returns"`
+	Deterministic string          `json:"deterministic"`
+	DataAccess    string          `json:"data_access"`
+	Security      string          `json:"security"`
+	Definition    string          `json:"definition"`
+	Origin        *sourceLocation `json:"origin,omitempty"`
+}
+
+// routineProperties lists the compared properties of a routine, in display order
+var routineProperties = []string{"parameters", "returns", "deterministic", "data_access", "security", "definition"}
+
+// Properties returns the comparable properties of this routine, keyed by name
+func (rs routineSummary) Properties() map[string]string {
+	return map[string]string{
+		"parameters":    rs.Parameters,
+		"returns":       rs.Returns,
+		"deterministic": rs.Deterministic,
+		"data_access":   rs.DataAccess,
+		"security":      rs.Security,
+		"definition":    rs.Definition,
+	}
+}
+
+type triggerSummary struct {
+	Name      string          `json:"name"`
+	Table     string          `json:"table"`
+	Timing    string          `json:"timing"`
+	Event     string          `json:"event"`
+	Statement string          `json:"statement"`
+	Origin    *sourceLocation `json:"origin,omitempty"`
+}
+
+// triggerProperties lists the compared properties of a trigger, in display order
+var triggerProperties = []string{"table", "timing", "event", "statement"}
+
+// Properties returns the comparable properties of this trigger, keyed by name
+func (ts triggerSummary) Properties() map[string]string {
+	return map[string]string{
+		"table":     ts.Table,
+		"timing":    ts.Timing,
+		"event":     ts.Event,
+		"statement": ts.Statement,
+	}
+}
+
 type databaseSummary struct {
-	Name   string          `json:"name"`
-	Tables []*tableSummary `json:"tables"`
+	Name     string            `json:"name"`
+	Tables   []*tableSummary   `json:"tables"`
+	Routines []*routineSummary `json:"routines"`
+	Triggers []*triggerSummary `json:"triggers"`
 }
 
 func (ds databaseSummary) TableNames() []string {
@@ -53,10 +294,50 @@ func (ds databaseSummary) FindTable(name string) (tableSummary, bool) {
 	return tableSummary{}, false
 }
 
+// RoutineNames returns the names of all routines of the provided type, e.g. "PROCEDURE" or "FUNCTION"
+func (ds databaseSummary) RoutineNames(rtype string) []string {
+	out := make([]string, 0)
+	for _, r := range ds.Routines {
+		if r.Type == rtype {
+			out = append(out, r.Name)
+		}
+	}
+	return out
+}
+
+func (ds databaseSummary) FindRoutine(rtype, name string) (routineSummary, bool) {
+	for _, r := range ds.Routines {
+		if r.Type == rtype && r.Name == name {
+			return *r, true
+		}
+	}
+	return routineSummary{}, false
+}
+
+func (ds databaseSummary) TriggerNames() []string {
+	out := make([]string, 0)
+	for _, t := range ds.Triggers {
+		out = append(out, t.Name)
+	}
+	return out
+}
+
+func (ds databaseSummary) FindTrigger(name string) (triggerSummary, bool) {
+	for _, t := range ds.Triggers {
+		if t.Name == name {
+			return *t, true
+		}
+	}
+	return triggerSummary{}, false
+}
+
 type connectionSummary struct {
-	Label     string             `json:"label"`
-	Address   string             `json:"address"`
-	Databases []*databaseSummary `json:"databases"`
+	Label         string             `json:"label"`
+	Address       string             `json:"address"`
+	Source        string             `json:"source,omitempty"`
+	ServerVersion string             `json:"server_version,omitempty"`
+	Hostname      string             `json:"hostname,omitempty"`
+	Databases     []*databaseSummary `json:"databases"`
 }
 
 func (cs connectionSummary) DisplayName() string {
@@ -68,6 +349,70 @@ func (cs connectionSummary) DisplayName() string {
 
 type connectionSummaries []*connectionSummary
 
+type summaryDatabase struct {
+	Ref     databaseRef
+	Summary *databaseSummary
+}
+
+// AllDatabases returns every database summary across all connections, in order
+func (cs connectionSummaries) AllDatabases() []summaryDatabase {
+	out := make([]summaryDatabase, 0)
+	for _, c := range cs {
+		for _, db := range c.Databases {
+			out = append(out, summaryDatabase{
+				Ref:     databaseRef{Connection: c.DisplayName(), Database: db.Name},
+				Summary: db,
+			})
+		}
+	}
+	return out
+}
+
+// Only returns copies of the connections holding only the provided databases, omitting connections left without any
+func (cs connectionSummaries) Only(dbs ...*databaseSummary) connectionSummaries {
+	out := make(connectionSummaries, 0)
+	for _, c := range cs {
+		cc := *c
+		cc.Databases = make([]*databaseSummary, 0)
+		for _, db := range c.Databases {
+			if slices.Contains(dbs, db) {
+				cc.Databases = append(cc.Databases, db)
+			}
+		}
+		if len(cc.Databases) > 0 {
+			out = append(out, &cc)
+		}
+	}
+	return out
+}
+
+// WithBaseline returns the connections with the named connection moved first, so that its first database is used as
+// the baseline of a diff.  The order of every other connection is preserved.  The name must identify a single
+// connection.
+func (cs connectionSummaries) WithBaseline(name string) (connectionSummaries, error) {
+	idx := -1
+	names := make([]string, len(cs))
+	for i, c := range cs {
+		names[i] = c.DisplayName()
+		if names[i] != name {
+			continue
+		}
+		if idx != -1 {
+			return nil, fmt.Errorf("baseline %q names more than one source, provide a unique label for each", name)
+		}
+		idx = i
+	}
+	if idx == -1 {
+		return nil, fmt.Errorf("baseline %q does not name a source, expected one of %v", name, names)
+	}
+
+	out := make(connectionSummaries, 0, len(cs))
+	out = append(out, cs[idx])
+	out = append(out, cs[:idx]...)
+	out = append(out, cs[idx+1:]...)
+	return out, nil
+}
+
 func (cs connectionSummaries) DatabaseNames() []string {
 	out := make([]string, 0)
 	for _, c := range cs {
@@ -136,6 +481,280 @@ func addColumnSummaries(ctx context.Context, conn *sql.DB, db string, tblsum *ta
 	return nil
 }
 
+func addRoutineSummaries(ctx context.Context, conn *sql.DB, db string, dbsum *databaseSummary) error {
+	tx, err := startTx(ctx, conn, db)
+	if err != nil {
+		return err
+	}
+
+	// always queue up rollback
+	defer func() { _ = tx.Rollback() }()
+
+	// build parameter lists first, keyed by routine type and name
+	params := make(map[string][]string)
+
+	prows, err := doQuery(
+		ctx,
+		tx,
+		"SELECT ROUTINE_TYPE, SPECIFIC_NAME, PARAMETER_MODE, PARAMETER_NAME, DTD_IDENTIFIER"+
+			" FROM information_schema.PARAMETERS"+
+			" WHERE SPECIFIC_SCHEMA = ? AND ORDINAL_POSITION > 0"+
+			" ORDER BY ROUTINE_TYPE, SPECIFIC_NAME, ORDINAL_POSITION;",
+		db,
+	)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = prows.Close() }()
+
+	for prows.Next() {
+		var rtype, rname, pname, dtd string
+		var pmode sql.NullString
+
+		if err = prows.Scan(&rtype, &rname, &pmode, &pname, &dtd); err != nil {
+			return fmt.Errorf("error scanning row: %w", err)
+		}
+
+		p := fmt.Sprintf("%s %s", pname, dtd)
+		if pmode.Valid {
+			p = fmt.Sprintf("%s %s", pmode.String, p)
+		}
+
+		params[rtype+"."+rname] = append(params[rtype+"."+rname], p)
+	}
+
+	rows, err := doQuery(
+		ctx,
+		tx,
+		"SELECT ROUTINE_NAME, ROUTINE_TYPE, DTD_IDENTIFIER, IS_DETERMINISTIC, SQL_DATA_ACCESS, SECURITY_TYPE, ROUTINE_DEFINITION"+
+			" FROM information_schema.ROUTINES"+
+			" WHERE ROUTINE_SCHEMA = ?"+
+			" ORDER BY ROUTINE_TYPE, ROUTINE_NAME;",
+		db,
+	)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var rsum routineSummary
+		var returns, definition sql.NullString
+
+		err = rows.Scan(&rsum.Name, &rsum.Type, &returns, &rsum.Deterministic, &rsum.DataAccess, &rsum.Security, &definition)
+		if err != nil {
+			return fmt.Errorf("error scanning row: %w", err)
+		}
+
+		rsum.Returns = returns.String
+		rsum.Definition = definition.String
+		rsum.Parameters = strings.Join(params[rsum.Type+"."+rsum.Name], ", ")
+
+		dbsum.Routines = append(dbsum.Routines, &rsum)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func addViewDefinitions(ctx context.Context, conn *sql.DB, db string, dbsum *databaseSummary) error {
+	tx, err := startTx(ctx, conn, db)
+	if err != nil {
+		return err
+	}
+
+	// always queue up rollback
+	defer func() { _ = tx.Rollback() }()
+
+	rows, err := doQuery(ctx, tx, "SELECT TABLE_NAME, VIEW_DEFINITION FROM information_schema.VIEWS WHERE TABLE_SCHEMA = ?;", db)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var name, definition string
+
+		if err = rows.Scan(&name, &definition); err != nil {
+			return fmt.Errorf("error scanning row: %w", err)
+		}
+
+		for _, tbl := range dbsum.Tables {
+			if tbl.Name == name {
+				tbl.Definition = definition
+			}
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func addIndexSummaries(ctx context.Context, conn *sql.DB, db string, dbsum *databaseSummary) error {
+	tx, err := startTx(ctx, conn, db)
+	if err != nil {
+		return err
+	}
+
+	// always queue up rollback
+	defer func() { _ = tx.Rollback() }()
+
+	rows, err := doQuery(
+		ctx,
+		tx,
+		"SELECT TABLE_NAME, INDEX_NAME, NON_UNIQUE, COLUMN_NAME"+
+			" FROM information_schema.STATISTICS"+
+			" WHERE TABLE_SCHEMA = ?"+
+			" ORDER BY TABLE_NAME, INDEX_NAME, SEQ_IN_INDEX;",
+		db,
+	)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var (
+			tname, iname string
+			nonUnique    int
+			column       sql.NullString
+		)
+
+		if err = rows.Scan(&tname, &iname, &nonUnique, &column); err != nil {
+			return fmt.Errorf("error scanning row: %w", err)
+		}
+
+		// functional index parts have no column name
+		if !column.Valid {
+			column.String = "(expression)"
+		}
+
+		for _, tbl := range dbsum.Tables {
+			if tbl.Name != tname {
+				continue
+			}
+			if n := len(tbl.Indexes); n > 0 && tbl.Indexes[n-1].Name == iname {
+				tbl.Indexes[n-1].Columns = append(tbl.Indexes[n-1].Columns, column.String)
+			} else {
+				tbl.Indexes = append(tbl.Indexes, indexSummary{Name: iname, Unique: nonUnique == 0, Columns: []string{column.String}})
+			}
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func addForeignKeySummaries(ctx context.Context, conn *sql.DB, db string, dbsum *databaseSummary) error {
+	tx, err := startTx(ctx, conn, db)
+	if err != nil {
+		return err
+	}
+
+	// always queue up rollback
+	defer func() { _ = tx.Rollback() }()
+
+	rows, err := doQuery(
+		ctx,
+		tx,
+		"SELECT TABLE_NAME, CONSTRAINT_NAME, COLUMN_NAME, REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME"+
+			" FROM information_schema.KEY_COLUMN_USAGE"+
+			" WHERE TABLE_SCHEMA = ? AND REFERENCED_TABLE_NAME IS NOT NULL"+
+			" ORDER BY TABLE_NAME, CONSTRAINT_NAME, ORDINAL_POSITION;",
+		db,
+	)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var tname, fname, column, refTable, refColumn string
+
+		if err = rows.Scan(&tname, &fname, &column, &refTable, &refColumn); err != nil {
+			return fmt.Errorf("error scanning row: %w", err)
+		}
+
+		for _, tbl := range dbsum.Tables {
+			if tbl.Name != tname {
+				continue
+			}
+			if n := len(tbl.ForeignKeys); n > 0 && tbl.ForeignKeys[n-1].Name == fname {
+				tbl.ForeignKeys[n-1].Columns = append(tbl.ForeignKeys[n-1].Columns, column)
+				tbl.ForeignKeys[n-1].RefColumns = append(tbl.ForeignKeys[n-1].RefColumns, refColumn)
+			} else {
+				tbl.ForeignKeys = append(tbl.ForeignKeys, foreignKeySummary{
+					Name:       fname,
+					Columns:    []string{column},
+					RefTable:   refTable,
+					RefColumns: []string{refColumn},
+				})
+			}
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func addTriggerSummaries(ctx context.Context, conn *sql.DB, db string, dbsum *databaseSummary) error {
+	tx, err := startTx(ctx, conn, db)
+	if err != nil {
+		return err
+	}
+
+	// always queue up rollback
+	defer func() { _ = tx.Rollback() }()
+
+	rows, err := doQuery(
+		ctx,
+		tx,
+		"SELECT TRIGGER_NAME, EVENT_OBJECT_TABLE, ACTION_TIMING, EVENT_MANIPULATION, ACTION_STATEMENT"+
+			" FROM information_schema.TRIGGERS"+
+			" WHERE TRIGGER_SCHEMA = ?"+
+			" ORDER BY TRIGGER_NAME;",
+		db,
+	)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var tsum triggerSummary
+
+		if err = rows.Scan(&tsum.Name, &tsum.Table, &tsum.Timing, &tsum.Event, &tsum.Statement); err != nil {
+			return fmt.Errorf("error scanning row: %w", err)
+		}
+
+		dbsum.Triggers = append(dbsum.Triggers, &tsum)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
+
 func summarizeDatabase(ctx context.Context, conn *sql.DB, db string) (*databaseSummary, error) {
 	tx, err := startTx(ctx, conn, db)
 	if err != nil {
@@ -154,8 +773,10 @@ func summarizeDatabase(ctx context.Context, conn *sql.DB, db string) (*databaseS
 	defer func() { _ = rows.Close() }()
 
 	dbsum := &databaseSummary{
-		Name:   db,
-		Tables: make([]*tableSummary, 0),
+		Name:     db,
+		Tables:   make([]*tableSummary, 0),
+		Routines: make([]*routineSummary, 0),
+		Triggers: make([]*triggerSummary, 0),
 	}
 
 	for rows.Next() {
@@ -166,9 +787,11 @@ func summarizeDatabase(ctx context.Context, conn *sql.DB, db string) (*databaseS
 		}
 
 		dbsum.Tables = append(dbsum.Tables, &tableSummary{
-			Name:    tname,
-			Type:    ttype,
-			Columns: make([]columnSummary, 0),
+			Name:        tname,
+			Type:        ttype,
+			Columns:     make([]columnSummary, 0),
+			Indexes:     make([]indexSummary, 0),
+			ForeignKeys: make([]foreignKeySummary, 0),
 		})
 	}
 
@@ -182,26 +805,74 @@ func summarizeDatabase(ctx context.Context, conn *sql.DB, db string) (*databaseS
 		}
 	}
 
+	if err = addViewDefinitions(ctx, conn, db, dbsum); err != nil {
+		return nil, fmt.Errorf("error summarizing database %q views: %w", db, err)
+	}
+
+	if err = addIndexSummaries(ctx, conn, db, dbsum); err != nil {
+		return nil, fmt.Errorf("error summarizing database %q indexes: %w", db, err)
+	}
+
+	if err = addForeignKeySummaries(ctx, conn, db, dbsum); err != nil {
+		return nil, fmt.Errorf("error summarizing database %q foreign keys: %w", db, err)
+	}
+
+	if err = addRoutineSummaries(ctx, conn, db, dbsum); err != nil {
+		return nil, fmt.Errorf("error summarizing database %q routines: %w", db, err)
+	}
+
+	if err = addTriggerSummaries(ctx, conn, db, dbsum); err != nil {
+		return nil, fmt.Errorf("error summarizing database %q triggers: %w", db, err)
+	}
+
 	return dbsum, nil
 }
 
-func summarizeConnections(ctx context.Context, conns []*mysqlConn) (connectionSummaries, error) {
+func summarizeConnection(ctx context.Context, cn *mysqlConn) (*connectionSummary, error) {
+	connStruct := &connectionSummary{
+		Label:     cn.Label,
+		Address:   cn.Address,
+		Source:    sourceMySQL,
+		Databases: make([]*databaseSummary, 0),
+	}
+
+	rows, err := doQuery(ctx, cn.Conn, "SELECT VERSION(), @@hostname;")
+	if err != nil {
+		return nil, fmt.Errorf("error fetching server metadata from %q: %w", cn.Address, err)
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	if rows.Next() {
+		if err = rows.Scan(&connStruct.ServerVersion, &connStruct.Hostname); err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", err)
+		}
+	}
+
+	if err = rows.Close(); err != nil {
+		return nil, fmt.Errorf("error fetching server metadata from %q: %w", cn.Address, err)
+	}
+
+	for _, db := range cn.Databases {
+		dbStruct, err := summarizeDatabase(ctx, cn.Conn, db)
+		if err != nil {
+			return nil, fmt.Errorf("error summarizing database %q in server %q: %w", db, cn.Address, err)
+		}
+		connStruct.Databases = append(connStruct.Databases, dbStruct)
+	}
+
+	return connStruct, nil
+}
+
+func summarizeSources(ctx context.Context, sources summarySources) (connectionSummaries, error) {
 	summaries := make(connectionSummaries, 0)
 
-	for _, cn := range conns {
-		connStruct := &connectionSummary{
-			Label:     cn.Label,
-			Address:   cn.Address,
-			Databases: make([]*databaseSummary, 0),
-		}
-		summaries = append(summaries, connStruct)
-		for _, db := range cn.Databases {
-			dbStruct, err := summarizeDatabase(ctx, cn.Conn, db)
-			if err != nil {
-				return nil, fmt.Errorf("error summarizing database %q in server %q: %w", db, cn.Address, err)
-			}
-			connStruct.Databases = append(connStruct.Databases, dbStruct)
+	for _, src := range sources {
+		sums, err := src.Summarize(ctx)
+		if err != nil {
+			return nil, err
 		}
+		summaries = append(summaries, sums...)
 	}
 
 	return summaries, nil
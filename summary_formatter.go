@@ -0,0 +1,43 @@
+package main
+
+import (
+	"io"
+	"slices"
+
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	summaryFormatters map[string]SummaryFormatConstructor
+)
+
+func init() {
+	summaryFormatters = map[string]SummaryFormatConstructor{
+		SummaryFormatJSON: newSummaryJSONFormatter,
+		SummaryFormatYAML: newSummaryYAMLFormatter,
+		SummaryFormatTOML: newSummaryTOMLFormatter,
+	}
+}
+
+func AvailableSummaryFormatters() []string {
+	out := make([]string, len(summaryFormatters))
+	i := 0
+	for k := range summaryFormatters {
+		out[i] = k
+		i++
+	}
+	slices.Sort(out)
+	return out
+}
+
+func BuildSummaryFormatter(cctx *cli.Context) (SummaryFormatter, error) {
+	return summaryFormatters[cctx.String(flagFormat)](cctx, cctx.Value(flagFormatConfig).(MapString))
+}
+
+// SummaryFormatter encodes the snapshot written by the summary command
+type SummaryFormatter interface {
+	Type() string
+	Render(*snapshotFile, io.Writer) error
+}
+
+type SummaryFormatConstructor func(*cli.Context, map[string]string) (SummaryFormatter, error)
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	SummaryFormatJSON = "json"
+)
+
+var _ SummaryFormatter = (*SummaryJSONFormatter)(nil)
+
+// SummaryJSONFormatter encodes the snapshot as JSON, the encoding read by -snapshot by default
+type SummaryJSONFormatter struct {
+	pretty bool
+}
+
+func newSummaryJSONFormatter(cctx *cli.Context, cfg map[string]string) (SummaryFormatter, error) {
+	var err error
+
+	jf := SummaryJSONFormatter{
+		pretty: cctx != nil && cctx.Bool(flagPretty),
+	}
+
+	if v, ok := cfg["pretty"]; ok {
+		if jf.pretty, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("error parsing flag \"pretty\" value %q as bool: %w", v, err)
+		}
+	}
+
+	return &jf, nil
+}
+
+func (*SummaryJSONFormatter) Type() string {
+	return SummaryFormatJSON
+}
+
+func (jf *SummaryJSONFormatter) Render(snap *snapshotFile, sink io.Writer) error {
+	var (
+		b   []byte
+		err error
+	)
+	if jf.pretty {
+		b, err = json.MarshalIndent(snap, "", "  ")
+	} else {
+		b, err = json.Marshal(snap)
+	}
+	if err != nil {
+		return fmt.Errorf("error json-marshalling summary: %w", err)
+	}
+
+	if _, err = sink.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("error writing output: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// fixtureSnapshot returns a snapshot of the base and target schemas with a fixed capture time and tool version
+func fixtureSnapshot(t *testing.T) *snapshotFile {
+	t.Helper()
+
+	snap := newSnapshotFile(fixtureSummaries(t))
+	snap.CapturedAt = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	snap.ToolVersion = "test"
+	return snap
+}
+
+func renderSummary(t *testing.T, format string, cctx *cli.Context, cfg map[string]string, snap *snapshotFile) []byte {
+	t.Helper()
+
+	f, err := summaryFormatters[format](cctx, cfg)
+	if err != nil {
+		t.Fatalf("error building %q summary formatter: %v", format, err)
+	}
+
+	var b bytes.Buffer
+	if err = f.Render(snap, &b); err != nil {
+		t.Fatalf("error rendering %q summary: %v", format, err)
+	}
+
+	return b.Bytes()
+}
+
+// prettyContext returns a context in which -pretty is set
+func prettyContext(t *testing.T) *cli.Context {
+	t.Helper()
+
+	set := flag.NewFlagSet("summary", flag.ContinueOnError)
+	set.Bool(flagPretty, false, "")
+	if err := set.Parse([]string{"-" + flagPretty}); err != nil {
+		t.Fatal(err)
+	}
+
+	return cli.NewContext(nil, set, nil)
+}
+
+func TestSummaryFormatterGolden(t *testing.T) {
+	snap := fixtureSnapshot(t)
+
+	tests := []struct {
+		format string
+		cfg    map[string]string
+		golden string
+	}{
+		{SummaryFormatJSON, map[string]string{"pretty": "true"}, "summary.json"},
+		{SummaryFormatYAML, nil, "summary.yaml"},
+		{SummaryFormatYAML, map[string]string{"indent": "4"}, "summary-indent.yaml"},
+		{SummaryFormatTOML, nil, "summary.toml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			assertGolden(t, tt.golden, renderSummary(t, tt.format, nil, tt.cfg, snap))
+		})
+	}
+}
+
+func TestSummaryFormatterRoundTrip(t *testing.T) {
+	snap := fixtureSnapshot(t)
+
+	want, err := json.Marshal(snap)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		format string
+		path   string
+	}{
+		{SummaryFormatJSON, "summary.json"},
+		{SummaryFormatYAML, "summary.yaml"},
+		{SummaryFormatTOML, "summary.toml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			b, err := snapshotJSON(tt.path, renderSummary(t, tt.format, nil, nil, snap))
+			if err != nil {
+				t.Fatalf("error converting to JSON: %v", err)
+			}
+
+			decoded, err := decodeSnapshot(b)
+			if err != nil {
+				t.Fatalf("error decoding snapshot: %v", err)
+			}
+
+			got, err := json.Marshal(decoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("round trip mismatch\ngot:  %s\nwant: %s", got, want)
+			}
+		})
+	}
+}
+
+func TestSummaryFormatterPretty(t *testing.T) {
+	cctx := prettyContext(t)
+
+	snap := fixtureSnapshot(t)
+	if b := renderSummary(t, SummaryFormatJSON, cctx, nil, snap); !bytes.Contains(b, []byte("\n  ")) {
+		t.Errorf("expected indented JSON with -pretty, got:\n%s", b)
+	}
+
+	for _, format := range []string{SummaryFormatYAML, SummaryFormatTOML} {
+		if _, err := summaryFormatters[format](cctx, nil); err == nil {
+			t.Errorf("expected -pretty to be rejected for %q", format)
+		}
+	}
+}
+
+func TestSummaryFormatterInvalidConfig(t *testing.T) {
+	tests := []struct {
+		format string
+		cfg    map[string]string
+	}{
+		{SummaryFormatJSON, map[string]string{"pretty": "yes please"}},
+		{SummaryFormatYAML, map[string]string{"indent": "wide"}},
+	}
+
+	for _, tt := range tests {
+		if _, err := summaryFormatters[tt.format](nil, tt.cfg); err == nil {
+			t.Errorf("expected %q config %v to be rejected", tt.format, tt.cfg)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/BurntSushi/toml"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	SummaryFormatTOML = "toml"
+)
+
+var _ SummaryFormatter = (*SummaryTOMLFormatter)(nil)
+
+// SummaryTOMLFormatter encodes the snapshot as TOML, using the same keys as the JSON encoding.  TOML has no null, so
+// null values such as a column without a default are omitted.
+type SummaryTOMLFormatter struct{}
+
+func newSummaryTOMLFormatter(cctx *cli.Context, _ map[string]string) (SummaryFormatter, error) {
+	if cctx != nil && cctx.Bool(flagPretty) {
+		return nil, fmt.Errorf("flag %q only applies to the %q format", flagPretty, SummaryFormatJSON)
+	}
+	return &SummaryTOMLFormatter{}, nil
+}
+
+func (*SummaryTOMLFormatter) Type() string {
+	return SummaryFormatTOML
+}
+
+// tomlValue converts a value decoded from JSON into one TOML can encode, dropping null values and converting numbers
+// into integers where possible.
+func tomlValue(v any) any {
+	switch tv := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(tv))
+		for k, e := range tv {
+			if e != nil {
+				out[k] = tomlValue(e)
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, 0, len(tv))
+		for _, e := range tv {
+			if e != nil {
+				out = append(out, tomlValue(e))
+			}
+		}
+		return out
+	case json.Number:
+		if i, err := tv.Int64(); err == nil {
+			return i
+		}
+		f, _ := tv.Float64()
+		return f
+	default:
+		return v
+	}
+}
+
+func (*SummaryTOMLFormatter) Render(snap *snapshotFile, sink io.Writer) error {
+	b, err := json.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("error json-marshalling summary: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var doc any
+	if err = dec.Decode(&doc); err != nil {
+		return fmt.Errorf("error decoding summary: %w", err)
+	}
+
+	if err = toml.NewEncoder(sink).Encode(tomlValue(doc)); err != nil {
+		return fmt.Errorf("error writing output: %w", err)
+	}
+
+	return nil
+}
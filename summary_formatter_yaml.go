@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	SummaryFormatYAML = "yaml"
+)
+
+var _ SummaryFormatter = (*SummaryYAMLFormatter)(nil)
+
+// SummaryYAMLFormatter encodes the snapshot as YAML, using the same keys as the JSON encoding and in the same order
+type SummaryYAMLFormatter struct {
+	indent int
+}
+
+func newSummaryYAMLFormatter(cctx *cli.Context, cfg map[string]string) (SummaryFormatter, error) {
+	var err error
+
+	if cctx != nil && cctx.Bool(flagPretty) {
+		return nil, fmt.Errorf("flag %q only applies to the %q format", flagPretty, SummaryFormatJSON)
+	}
+
+	yf := SummaryYAMLFormatter{
+		indent: 2,
+	}
+
+	if v, ok := cfg["indent"]; ok {
+		if yf.indent, err = strconv.Atoi(v); err != nil {
+			return nil, fmt.Errorf("error parsing flag \"indent\" value %q as int: %w", v, err)
+		}
+		if yf.indent < 2 {
+			return nil, fmt.Errorf("flag \"indent\" must be at least 2, saw %d", yf.indent)
+		}
+	}
+
+	return &yf, nil
+}
+
+func (*SummaryYAMLFormatter) Type() string {
+	return SummaryFormatYAML
+}
+
+// yamlOldBools lists the strings YAML 1.1 parsers read as booleans, such as the "NO" of a column's nullability
+var yamlOldBools = []string{"y", "yes", "n", "no", "on", "off"}
+
+// blockStyle clears the style of every node decoded from JSON, so that mappings and sequences are written in block
+// style and scalars are only quoted where YAML requires it.  Strings which YAML 1.1 parsers would read as booleans
+// remain quoted.
+func blockStyle(n *yaml.Node) {
+	n.Style = 0
+	if n.Kind == yaml.ScalarNode && n.Tag == "!!str" && slices.Contains(yamlOldBools, strings.ToLower(n.Value)) {
+		n.Style = yaml.DoubleQuotedStyle
+	}
+	for _, c := range n.Content {
+		blockStyle(c)
+	}
+}
+
+func (yf *SummaryYAMLFormatter) Render(snap *snapshotFile, sink io.Writer) error {
+	b, err := json.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("error json-marshalling summary: %w", err)
+	}
+
+	// JSON is valid YAML, and decoding it into a node retains the order of its keys
+	var doc yaml.Node
+	if err = yaml.Unmarshal(b, &doc); err != nil {
+		return fmt.Errorf("error decoding summary: %w", err)
+	}
+	blockStyle(&doc)
+
+	enc := yaml.NewEncoder(sink)
+	enc.SetIndent(yf.indent)
+
+	if err = enc.Encode(&doc); err != nil {
+		return fmt.Errorf("error writing output: %w", err)
+	}
+
+	if err = enc.Close(); err != nil {
+		return fmt.Errorf("error writing output: %w", err)
+	}
+
+	return nil
+}
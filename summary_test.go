@@ -0,0 +1,58 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestConnectionSummariesWithBaseline(t *testing.T) {
+	summaries := connectionSummaries{{Label: "a"}, {Address: "b:3306"}, {Label: "c"}}
+
+	names := func(cs connectionSummaries) []string {
+		out := make([]string, len(cs))
+		for i, c := range cs {
+			out[i] = c.DisplayName()
+		}
+		return out
+	}
+
+	tests := []struct {
+		baseline string
+		want     []string
+	}{
+		{"a", []string{"a", "b:3306", "c"}},
+		{"b:3306", []string{"b:3306", "a", "c"}},
+		{"c", []string{"c", "a", "b:3306"}},
+	}
+
+	for _, tt := range tests {
+		got, err := summaries.WithBaseline(tt.baseline)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(names(got), tt.want) {
+			t.Errorf("expected baseline %q to order sources %v, got %v", tt.baseline, tt.want, names(got))
+		}
+	}
+
+	if _, err := summaries.WithBaseline("missing"); err == nil {
+		t.Error("expected error for unknown baseline")
+	}
+}
+
+func TestConnectionSummariesWithBaselineDuplicates(t *testing.T) {
+	// sources sharing a name are accepted unless the name is used as the baseline
+	summaries := connectionSummaries{{Label: "a"}, {Address: "b:3306"}, {Address: "b:3306"}, {Label: "b:3306"}}
+
+	got, err := summaries.WithBaseline("a")
+	if err != nil {
+		t.Fatalf("expected a unique baseline to be accepted, got %v", err)
+	}
+	if len(got) != len(summaries) || got[0] != summaries[0] {
+		t.Errorf("expected %q first with every source kept, got %v", "a", got)
+	}
+
+	if _, err = summaries.WithBaseline("b:3306"); err == nil {
+		t.Error("expected a baseline naming more than one source to be rejected")
+	}
+}
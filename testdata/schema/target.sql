@@ -0,0 +1,66 @@
+-- MySQL dump 10.13  Distrib 8.0.36, for Linux (x86_64)
+/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */;
+/*!40101 SET NAMES utf8mb4 */;
+
+CREATE DATABASE /*!32312 IF NOT EXISTS*/ `shop` /*!40100 DEFAULT CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci */ /*!80016 DEFAULT ENCRYPTION='N' */;
+
+USE `shop`;
+
+DROP TABLE IF EXISTS `customers`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+CREATE TABLE `customers` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `email` varchar(255) COLLATE utf8mb4_bin NOT NULL,
+  `name` varchar(120) DEFAULT NULL,
+  `status` enum('active','disabled') NOT NULL DEFAULT 'active',
+  `balance` decimal(10,2) NOT NULL DEFAULT '0.00',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT NULL ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `email` (`email`)
+) ENGINE=InnoDB AUTO_INCREMENT=42 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+LOCK TABLES `customers` WRITE;
+/*!40000 ALTER TABLE `customers` DISABLE KEYS */;
+INSERT INTO `customers` VALUES (1,'a;b','x','active',0.00,NOW(),NULL);
+UNLOCK TABLES;
+
+CREATE TABLE `orders` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `customer_id` int unsigned NOT NULL,
+  `total` decimal(12,2) DEFAULT NULL,
+  `note` text,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `orders_ibfk_1` FOREIGN KEY (`customer_id`) REFERENCES `customers` (`id`) ON DELETE RESTRICT
+) ENGINE=InnoDB;
+
+CREATE TABLE `audit` (
+  `id` bigint NOT NULL,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB;
+
+DROP TABLE IF EXISTS `active_customers`;
+/*!50001 DROP VIEW IF EXISTS `active_customers`*/;
+/*!50001 CREATE VIEW `active_customers` AS SELECT 
+ 1 AS `id`,
+ 1 AS `email`*/;
+
+DELIMITER ;;
+/*!50003 CREATE*/ /*!50017 DEFINER=`root`@`localhost`*/ /*!50003 TRIGGER `orders_bi` BEFORE INSERT ON `orders` FOR EACH ROW BEGIN
+  IF NEW.total < 0 THEN SET NEW.total = 0; END IF;
+END */;;
+DELIMITER ;
+
+DELIMITER ;;
+CREATE DEFINER=`root`@`%` PROCEDURE `touch`(IN cid INT, OUT n varchar(10) CHARSET utf8mb4)
+    READS SQL DATA
+BEGIN
+  SELECT COUNT(*) INTO n FROM orders WHERE customer_id = cid;
+END ;;
+DELIMITER ;
+
+/*!50001 DROP VIEW IF EXISTS `active_customers`*/;
+/*!50001 SET @saved_cs_client          = @@character_set_client */;
+/*!50001 CREATE ALGORITHM=UNDEFINED */
+/*!50013 DEFINER=`root`@`localhost` SQL SECURITY DEFINER */
+/*!50001 VIEW `active_customers` AS select `c`.`id` AS `id`,`c`.`email` AS `email`, concat(`c`.`name`, '!') AS `shout` from `customers` `c` where (`c`.`status` = 'active') */;